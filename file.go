@@ -13,6 +13,9 @@
 package gopherun
 
 import (
+	"context"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -114,3 +117,63 @@ func (i GopherunFile) WriteFileSafer(writePath string, data []byte, perm os.File
 	}
 	return
 }
+
+// ErrSameFile 复制的源文件与目标文件是同一个文件（包括通过硬链接或符号链接指向同一文件）
+var ErrSameFile = errors.New("source and destination are the same file")
+
+// CopyFile 将 src 文件内容复制到 dst，返回复制的字节数；dst 已存在时覆盖其内容，并将权限设置为与 src 相同。
+// dst 与 src 是同一个文件时返回 ErrSameFile，不会截断 src
+func (i GopherunFile) CopyFile(src, dst string) (written int64, err error) {
+	return i.copyFile(context.Background(), src, dst)
+}
+
+// copyFile 复制文件，ctx 可取消时每次读取前检查 ctx，大文件可以在复制中途取消
+func (i GopherunFile) copyFile(ctx context.Context, src, dst string) (written int64, err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return 0, err
+	}
+	// O_TRUNC 打开同一个文件会清空 src
+	if dstInfo, err := os.Stat(dst); err == nil && os.SameFile(info, dstInfo) {
+		return 0, &os.PathError{Op: "copy", Path: dst, Err: ErrSameFile}
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	// OpenFile 的权限只在新建时生效，已存在的 dst 需要单独设置
+	if err = out.Chmod(info.Mode().Perm()); err != nil {
+		return 0, err
+	}
+
+	var reader io.Reader = in
+	if ctx.Done() != nil {
+		reader = contextReader{ctx: ctx, r: in}
+	}
+	return io.Copy(out, reader)
+}
+
+// contextReader 每次读取前检查 ctx 是否已取消
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrFileNotFound 在搜索路径中未找到文件
+	ErrFileNotFound = errors.New("file not found in search paths")
+
+	// ErrUnsafeRuntimeDir 退回使用的运行时目录不是当前用户私有的目录
+	ErrUnsafeRuntimeDir = errors.New("unsafe runtime directory")
+)
+
+// 应用目录的默认权限，XDG 规范要求运行时目录为 0700，其余目录沿用同一权限
+const _appDirMode os.FileMode = 0700
+
+// ExecutableDir 返回当前可执行文件所在的目录（已解析符号链接）
+func (i GopherunFile) ExecutableDir() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(exe)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(resolved), nil
+}
+
+// XDGConfigDir 返回 $XDG_CONFIG_HOME/app（默认 ~/.config/app），目录不存在时自动创建
+func (i GopherunFile) XDGConfigDir(app string) (string, error) {
+	return i.xdgAppDir("XDG_CONFIG_HOME", ".config", app)
+}
+
+// XDGCacheDir 返回 $XDG_CACHE_HOME/app（默认 ~/.cache/app），目录不存在时自动创建
+func (i GopherunFile) XDGCacheDir(app string) (string, error) {
+	return i.xdgAppDir("XDG_CACHE_HOME", ".cache", app)
+}
+
+// XDGDataDir 返回 $XDG_DATA_HOME/app（默认 ~/.local/share/app），目录不存在时自动创建
+func (i GopherunFile) XDGDataDir(app string) (string, error) {
+	return i.xdgAppDir("XDG_DATA_HOME", filepath.Join(".local", "share"), app)
+}
+
+// XDGStateDir 返回 $XDG_STATE_HOME/app（默认 ~/.local/state/app），目录不存在时自动创建
+func (i GopherunFile) XDGStateDir(app string) (string, error) {
+	return i.xdgAppDir("XDG_STATE_HOME", filepath.Join(".local", "state"), app)
+}
+
+// XDGRuntimeDir 返回 $XDG_RUNTIME_DIR/app，目录不存在时自动创建。
+// 未设置时退回到系统临时目录下的 app 目录；临时目录是共享的，该路径可能被其他用户预先创建，
+// 因此只接受当前用户所有、权限为 0700 的目录（不跟随符号链接），否则返回 ErrUnsafeRuntimeDir
+func (i GopherunFile) XDGRuntimeDir(app string) (string, error) {
+	if base := xdgEnvDir("XDG_RUNTIME_DIR"); base != "" {
+		return i.mkAppDir(base, app)
+	}
+
+	dir := filepath.Join(os.TempDir(), app)
+	if err := os.Mkdir(dir, _appDirMode); err != nil && !os.IsExist(err) {
+		return "", err
+	}
+	if err := checkPrivateDir(dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// ConfigSearchPaths 返回应用配置文件的默认搜索目录，优先级从高到低依次为：
+// 当前目录、可执行文件目录、$XDG_CONFIG_HOME/app、$XDG_CONFIG_DIRS 中的每一项/app、/etc/app
+func (i GopherunFile) ConfigSearchPaths(app string) []string {
+	paths := []string{"."}
+	if exeDir, err := i.ExecutableDir(); err == nil {
+		paths = append(paths, exeDir)
+	}
+	if configHome, err := xdgBaseDir("XDG_CONFIG_HOME", ".config"); err == nil {
+		paths = append(paths, filepath.Join(configHome, app))
+	}
+
+	configDirs := os.Getenv("XDG_CONFIG_DIRS")
+	if configDirs == "" {
+		configDirs = "/etc/xdg"
+	}
+	for _, dir := range filepath.SplitList(configDirs) {
+		if filepath.IsAbs(dir) {
+			paths = append(paths, filepath.Join(dir, app))
+		}
+	}
+
+	return append(paths, filepath.Join("/etc", app))
+}
+
+// FindFile 按顺序在 dirs 中查找名为 name 的普通文件，返回第一个命中的绝对路径；
+// dirs 中的 ~ 与环境变量会被展开，全部未命中时返回 ErrFileNotFound。
+func (i GopherunFile) FindFile(name string, dirs ...string) (string, error) {
+	for _, dir := range dirs {
+		normalized, err := i.NormalizePath(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(normalized); err == nil && info.Mode().IsRegular() {
+			return normalized, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s in [%s]", ErrFileNotFound, name, strings.Join(dirs, ", "))
+}
+
+// FindConfigFile 在 ConfigSearchPaths(app) 中查找配置文件 name
+func (i GopherunFile) FindConfigFile(app, name string) (string, error) {
+	return i.FindFile(name, i.ConfigSearchPaths(app)...)
+}
+
+// xdgAppDir 返回 XDG 基础目录下的应用子目录，并确保其存在
+func (i GopherunFile) xdgAppDir(env, fallback, app string) (string, error) {
+	base, err := xdgBaseDir(env, fallback)
+	if err != nil {
+		return "", err
+	}
+	return i.mkAppDir(base, app)
+}
+
+// mkAppDir 创建 base/app 目录，app 为空时仅确保 base 存在
+func (i GopherunFile) mkAppDir(base, app string) (string, error) {
+	dir := filepath.Join(base, app)
+	if err := i.MkdirAllWithMode(dir, _appDirMode); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// xdgBaseDir 读取 XDG 环境变量，未设置或非绝对路径时使用 ~/fallback
+func xdgBaseDir(env, fallback string) (string, error) {
+	if dir := xdgEnvDir(env); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, fallback), nil
+}
+
+// xdgEnvDir 读取 XDG 环境变量，规范要求忽略相对路径
+func xdgEnvDir(env string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	return ""
+}
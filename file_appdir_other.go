@@ -0,0 +1,32 @@
+//go:build !unix
+
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"fmt"
+	"os"
+)
+
+// checkPrivateDir 当前平台的临时目录按用户隔离，只检查 dir 是目录而不是符号链接
+func checkPrivateDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", ErrUnsafeRuntimeDir, dir)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+type FileAppDirTest struct {
+	BaseTest
+}
+
+func TestFileAppDirTest(t *testing.T) {
+	suite.Run(t, new(FileAppDirTest))
+}
+
+func (f *FileAppDirTest) TestGopherunFile_ExecutableDir() {
+	dir, err := File.ExecutableDir()
+	require.Nil(f.T(), err)
+	require.True(f.T(), filepath.IsAbs(dir))
+	require.True(f.T(), File.IsDir(dir))
+}
+
+func (f *FileAppDirTest) TestGopherunFile_XDGDirs_case1() {
+	tempDir := f.T().TempDir()
+	f.T().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+	f.T().Setenv("XDG_CACHE_HOME", filepath.Join(tempDir, "cache"))
+	f.T().Setenv("XDG_DATA_HOME", filepath.Join(tempDir, "data"))
+	f.T().Setenv("XDG_STATE_HOME", filepath.Join(tempDir, "state"))
+	f.T().Setenv("XDG_RUNTIME_DIR", filepath.Join(tempDir, "runtime"))
+
+	dirFuncs := map[string]func(string) (string, error){
+		"config":  File.XDGConfigDir,
+		"cache":   File.XDGCacheDir,
+		"data":    File.XDGDataDir,
+		"state":   File.XDGStateDir,
+		"runtime": File.XDGRuntimeDir,
+	}
+	for name, dirFunc := range dirFuncs {
+		dir, err := dirFunc("myapp")
+		require.Nil(f.T(), err)
+		require.Equal(f.T(), filepath.Join(tempDir, name, "myapp"), dir)
+		require.DirExists(f.T(), dir)
+
+		stat, err := os.Stat(dir)
+		require.Nil(f.T(), err)
+		require.Equal(f.T(), os.FileMode(0700), stat.Mode().Perm())
+	}
+}
+
+func (f *FileAppDirTest) TestGopherunFile_XDGDirs_case2() {
+	// 相对路径的环境变量会被忽略，退回到主目录下的默认位置
+	home := f.T().TempDir()
+	f.T().Setenv("HOME", home)
+	f.T().Setenv("XDG_CONFIG_HOME", "relative/config")
+	f.T().Setenv("XDG_DATA_HOME", "")
+
+	dir, err := File.XDGConfigDir("myapp")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), filepath.Join(home, ".config", "myapp"), dir)
+
+	dir, err = File.XDGDataDir("myapp")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), filepath.Join(home, ".local", "share", "myapp"), dir)
+}
+
+func (f *FileAppDirTest) TestGopherunFile_XDGRuntimeDir_fallback() {
+	if runtime.GOOS == "windows" {
+		f.T().Skip("temporary directory is private to the user on windows")
+	}
+	tempDir := f.T().TempDir()
+	f.T().Setenv("XDG_RUNTIME_DIR", "")
+	f.T().Setenv("TMPDIR", tempDir)
+
+	// 不存在时创建为 0700，已存在的私有目录可以复用
+	for idx := 0; idx < 2; idx++ {
+		dir, err := File.XDGRuntimeDir("myapp")
+		require.Nil(f.T(), err)
+		require.Equal(f.T(), filepath.Join(tempDir, "myapp"), dir)
+		stat, err := os.Stat(dir)
+		require.Nil(f.T(), err)
+		require.Equal(f.T(), os.FileMode(0700), stat.Mode().Perm())
+	}
+
+	// 他人可以预先创建的目录：权限过宽、符号链接、普通文件
+	require.Nil(f.T(), os.Mkdir(filepath.Join(tempDir, "shared"), 0755))
+	require.Nil(f.T(), os.Chmod(filepath.Join(tempDir, "shared"), 0755))
+	require.Nil(f.T(), os.Symlink(filepath.Join(tempDir, "myapp"), filepath.Join(tempDir, "linked")))
+	require.Nil(f.T(), os.WriteFile(filepath.Join(tempDir, "file"), nil, 0600))
+	apps := []string{"shared", "linked", "file"}
+	if os.Getuid() == 0 {
+		require.Nil(f.T(), os.Mkdir(filepath.Join(tempDir, "other"), 0700))
+		require.Nil(f.T(), os.Chown(filepath.Join(tempDir, "other"), 65534, 65534))
+		apps = append(apps, "other")
+	}
+	for _, app := range apps {
+		_, err := File.XDGRuntimeDir(app)
+		require.True(f.T(), errors.Is(err, ErrUnsafeRuntimeDir), "%s: %v", app, err)
+	}
+}
+
+func (f *FileAppDirTest) TestGopherunFile_ConfigSearchPaths() {
+	tempDir := f.T().TempDir()
+	f.T().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+	f.T().Setenv("XDG_CONFIG_DIRS", "/opt/xdg:relative")
+
+	paths := File.ConfigSearchPaths("myapp")
+	require.Equal(f.T(), ".", paths[0])
+	require.Contains(f.T(), paths, filepath.Join(tempDir, "config", "myapp"))
+	require.Contains(f.T(), paths, "/opt/xdg/myapp")
+	require.NotContains(f.T(), paths, filepath.Join("relative", "myapp"))
+	require.Equal(f.T(), "/etc/myapp", paths[len(paths)-1])
+}
+
+func (f *FileAppDirTest) TestGopherunFile_FindFile() {
+	tempDir := f.T().TempDir()
+	first := filepath.Join(tempDir, "first")
+	second := filepath.Join(tempDir, "second")
+	require.Nil(f.T(), File.MkdirAll(first))
+	require.Nil(f.T(), File.MkdirAll(second))
+	require.Nil(f.T(), File.WriteFileSafer(filepath.Join(second, "config.json"), []byte("{}"), 0644))
+
+	// 同名目录不会被当作文件命中
+	require.Nil(f.T(), File.MkdirAll(filepath.Join(first, "config.json")))
+
+	f.T().Setenv("GOPHERUN_TEST_DIR", tempDir)
+	path, err := File.FindFile("config.json", first, "$GOPHERUN_TEST_DIR/second")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), filepath.Join(second, "config.json"), path)
+
+	_, err = File.FindFile("missing.json", first, second)
+	require.True(f.T(), errors.Is(err, ErrFileNotFound))
+}
+
+func (f *FileAppDirTest) TestGopherunFile_FindConfigFile() {
+	tempDir := f.T().TempDir()
+	f.T().Setenv("XDG_CONFIG_HOME", tempDir)
+
+	dir, err := File.XDGConfigDir("myapp")
+	require.Nil(f.T(), err)
+	require.Nil(f.T(), File.WriteFileSafer(filepath.Join(dir, "gopherun-test.json"), []byte("{}"), 0644))
+
+	path, err := File.FindConfigFile("myapp", "gopherun-test.json")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), filepath.Join(dir, "gopherun-test.json"), path)
+}
@@ -0,0 +1,39 @@
+//go:build unix
+
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkPrivateDir 检查 dir 是当前用户所有、权限为 0700 的目录，符号链接不被接受
+func checkPrivateDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	switch {
+	case !info.IsDir():
+		return fmt.Errorf("%w: %s is not a directory", ErrUnsafeRuntimeDir, dir)
+	case !ok || int(stat.Uid) != os.Getuid():
+		return fmt.Errorf("%w: %s is not owned by the current user", ErrUnsafeRuntimeDir, dir)
+	case info.Mode().Perm() != _appDirMode:
+		return fmt.Errorf("%w: %s has mode %v, want %v", ErrUnsafeRuntimeDir, dir, info.Mode().Perm(), _appDirMode)
+	}
+	return nil
+}
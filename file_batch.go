@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BatchOp 批量任务中对单个路径执行的操作，返回本次处理的字节数
+type BatchOp func(ctx context.Context, path string) (int64, error)
+
+// BatchProgress 批量任务的进度快照
+type BatchProgress struct {
+	Path       string        // 刚处理完成的路径
+	Err        error         // 该路径的处理结果
+	Done       int           // 已完成的路径数（含失败）
+	Total      int           // 路径总数
+	Bytes      int64         // 已处理的字节数
+	TotalBytes int64         // 预估的总字节数，无法统计时为 0
+	Elapsed    time.Duration // 已耗时
+	ETA        time.Duration // 预计剩余时间，无法估算时为 0
+}
+
+// BatchOptions 批量任务配置
+type BatchOptions struct {
+	// Workers 并发数，<= 0 时使用 CPU 核数
+	Workers int
+
+	// OnProgress 每处理完一个路径回调一次，回调是串行执行的
+	OnProgress func(progress BatchProgress)
+}
+
+// BatchError 批量任务中失败路径的汇总
+type BatchError struct {
+	// Errors 失败路径与对应错误
+	Errors map[string]error
+
+	// Cause 有路径失败且任务被取消时为 context 的错误，否则为 nil
+	Cause error
+}
+
+func (e *BatchError) Error() string {
+	paths := make([]string, 0, len(e.Errors))
+	for path := range e.Errors {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var builder strings.Builder
+	if e.Cause != nil {
+		builder.WriteString(e.Cause.Error())
+		builder.WriteString(": ")
+	}
+	fmt.Fprintf(&builder, "%d path(s) failed", len(paths))
+	for _, path := range paths {
+		fmt.Fprintf(&builder, "\n\t%s: %v", path, e.Errors[path])
+	}
+	return builder.String()
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Cause
+}
+
+// Batch 使用有界的 worker 池对 paths 并发执行 op。
+// 单个路径失败不会中断任务，所有失败会汇总为 *BatchError 返回；
+// ctx 取消后不再处理新的路径，未处理的路径记为 ctx.Err()，返回的 BatchError.Cause 为 ctx.Err()；
+// 所有路径都已成功处理时返回 nil。op 应自行响应 ctx，BatchCopy 与 BatchHash 会在复制中途检查 ctx。
+func (i GopherunFile) Batch(ctx context.Context, paths []string, op BatchOp, opts BatchOptions) error {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	// 仅在需要进度时统计总字节数
+	var totalBytes int64
+	if opts.OnProgress != nil {
+		for _, path := range paths {
+			if info, err := os.Lstat(path); err == nil && info.Mode().IsRegular() {
+				totalBytes += info.Size()
+			}
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		errs     = map[string]error{}
+		progress = BatchProgress{Total: len(paths), TotalBytes: totalBytes}
+		start    = time.Now()
+		jobs     = make(chan string)
+	)
+
+	finish := func(path string, n int64, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil {
+			errs[path] = err
+		}
+		if opts.OnProgress == nil {
+			return
+		}
+		progress.Path, progress.Err = path, err
+		progress.Done++
+		progress.Bytes += n
+		progress.Elapsed = time.Since(start)
+		progress.ETA = estimateBatchETA(progress)
+		opts.OnProgress(progress)
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				n, err := op(ctx, path)
+				finish(path, n, err)
+			}
+		}()
+	}
+
+	var dispatched int
+dispatch:
+	for _, path := range paths {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- path:
+			dispatched++
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, path := range paths[dispatched:] {
+		finish(path, 0, ctx.Err())
+	}
+
+	// 所有路径都已成功处理时，之后的取消不影响结果
+	if len(errs) == 0 {
+		return nil
+	}
+	return &BatchError{Errors: errs, Cause: ctx.Err()}
+}
+
+// estimateBatchETA 优先按字节估算剩余时间，没有字节信息时按完成数估算
+func estimateBatchETA(p BatchProgress) time.Duration {
+	switch {
+	case p.TotalBytes > 0 && p.Bytes > 0 && p.Bytes <= p.TotalBytes:
+		return time.Duration(float64(p.Elapsed) * float64(p.TotalBytes-p.Bytes) / float64(p.Bytes))
+	case p.Done > 0:
+		return time.Duration(float64(p.Elapsed) * float64(p.Total-p.Done) / float64(p.Done))
+	default:
+		return 0
+	}
+}
+
+// BatchRemove 并发删除 paths 中的文件或目录（目录会被递归删除）
+func (i GopherunFile) BatchRemove(ctx context.Context, paths []string, opts BatchOptions) error {
+	return i.Batch(ctx, paths, func(ctx context.Context, path string) (int64, error) {
+		var size int64
+		if info, err := os.Lstat(path); err == nil && info.Mode().IsRegular() {
+			size = info.Size()
+		}
+		return size, i.RemoveAll(path)
+	}, opts)
+}
+
+// BatchCopy 并发复制文件，files 的键为源路径、值为目标路径，目标目录不存在时自动创建
+func (i GopherunFile) BatchCopy(ctx context.Context, files map[string]string, opts BatchOptions) error {
+	sources := make([]string, 0, len(files))
+	for src := range files {
+		sources = append(sources, src)
+	}
+	sort.Strings(sources)
+
+	return i.Batch(ctx, sources, func(ctx context.Context, src string) (int64, error) {
+		dst := files[src]
+		if err := i.MkdirAll(filepath.Dir(dst)); err != nil {
+			return 0, err
+		}
+		return i.copyFile(ctx, src, dst)
+	}, opts)
+}
+
+// BatchHash 并发计算文件的 SHA-256，返回路径到十六进制摘要的映射；失败的路径不在结果中
+func (i GopherunFile) BatchHash(ctx context.Context, paths []string, opts BatchOptions) (map[string]string, error) {
+	var (
+		mu     sync.Mutex
+		hashes = make(map[string]string, len(paths))
+	)
+	err := i.Batch(ctx, paths, func(ctx context.Context, path string) (int64, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+
+		hash := sha256.New()
+		n, err := io.Copy(hash, contextReader{ctx: ctx, r: f})
+		if err != nil {
+			return n, err
+		}
+
+		mu.Lock()
+		hashes[path] = hex.EncodeToString(hash.Sum(nil))
+		mu.Unlock()
+		return n, nil
+	}, opts)
+	return hashes, err
+}
@@ -0,0 +1,218 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type FileBatchTest struct {
+	BaseTest
+}
+
+func TestFileBatchTest(t *testing.T) {
+	suite.Run(t, new(FileBatchTest))
+}
+
+// createBatchFiles 在临时目录下创建 count 个内容为 content 的文件
+func (f *FileBatchTest) createBatchFiles(count int, content string) []string {
+	tempDir := f.T().TempDir()
+	paths := make([]string, 0, count)
+	for n := 0; n < count; n++ {
+		path := filepath.Join(tempDir, fmt.Sprintf("file%03d.txt", n))
+		require.Nil(f.T(), os.WriteFile(path, []byte(content), 0644))
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func (f *FileBatchTest) TestGopherunFile_Batch_case1() {
+	paths := f.createBatchFiles(50, "0123456789")
+
+	var (
+		running    int32
+		maxRunning int32
+		last       BatchProgress
+		calls      int
+	)
+	err := File.Batch(context.Background(), paths, func(ctx context.Context, path string) (int64, error) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			observed := atomic.LoadInt32(&maxRunning)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return 10, nil
+	}, BatchOptions{
+		Workers: 4,
+		OnProgress: func(progress BatchProgress) {
+			calls++
+			last = progress
+		},
+	})
+
+	require.Nil(f.T(), err)
+	require.True(f.T(), maxRunning <= 4, "max running workers: %d", maxRunning)
+	require.Equal(f.T(), 50, calls)
+	require.Equal(f.T(), 50, last.Done)
+	require.Equal(f.T(), 50, last.Total)
+	require.Equal(f.T(), int64(500), last.Bytes)
+	require.Equal(f.T(), int64(500), last.TotalBytes)
+	require.Equal(f.T(), time.Duration(0), last.ETA)
+}
+
+func (f *FileBatchTest) TestGopherunFile_Batch_case2() {
+	paths := f.createBatchFiles(10, "x")
+	mockErr := errors.New("mock err")
+
+	// 单个路径失败不会中断其他路径
+	var processed int32
+	err := File.Batch(context.Background(), paths, func(ctx context.Context, path string) (int64, error) {
+		atomic.AddInt32(&processed, 1)
+		if path == paths[3] || path == paths[7] {
+			return 0, mockErr
+		}
+		return 1, nil
+	}, BatchOptions{Workers: 2})
+
+	require.Equal(f.T(), int32(10), processed)
+	var batchErr *BatchError
+	require.True(f.T(), errors.As(err, &batchErr))
+	require.Len(f.T(), batchErr.Errors, 2)
+	require.Equal(f.T(), mockErr, batchErr.Errors[paths[3]])
+	require.Nil(f.T(), batchErr.Cause)
+	require.Contains(f.T(), err.Error(), "2 path(s) failed")
+}
+
+func (f *FileBatchTest) TestGopherunFile_Batch_case3() {
+	paths := f.createBatchFiles(20, "x")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 处理到一半时取消
+	var processed int32
+	err := File.Batch(ctx, paths, func(ctx context.Context, path string) (int64, error) {
+		if atomic.AddInt32(&processed, 1) == 5 {
+			cancel()
+		}
+		return 1, nil
+	}, BatchOptions{Workers: 1})
+
+	require.True(f.T(), errors.Is(err, context.Canceled))
+	require.True(f.T(), processed < 20)
+
+	var batchErr *BatchError
+	require.True(f.T(), errors.As(err, &batchErr))
+	require.Equal(f.T(), 20-int(processed), len(batchErr.Errors))
+}
+
+func (f *FileBatchTest) TestGopherunFile_Batch_case4() {
+	paths := f.createBatchFiles(3, "x")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 所有路径处理完成后才取消，结果仍为成功
+	var processed int32
+	err := File.Batch(ctx, paths, func(ctx context.Context, path string) (int64, error) {
+		if atomic.AddInt32(&processed, 1) == int32(len(paths)) {
+			cancel()
+		}
+		return 1, nil
+	}, BatchOptions{Workers: 1})
+	require.Nil(f.T(), err)
+}
+
+func (f *FileBatchTest) TestGopherunFile_Batch_copyCancel() {
+	paths := f.createBatchFiles(1, strings.Repeat("x", 1<<20))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// 读取第一块后取消，复制在中途停止
+	reader := contextReader{ctx: ctx, r: &cancelReader{r: strings.NewReader(strings.Repeat("x", 1<<20)), cancel: cancel}}
+	n, err := io.Copy(io.Discard, reader)
+	require.True(f.T(), errors.Is(err, context.Canceled))
+	require.True(f.T(), n < 1<<20)
+
+	dst := filepath.Join(f.T().TempDir(), "copy.txt")
+	_, err = File.copyFile(ctx, paths[0], dst)
+	require.True(f.T(), errors.Is(err, context.Canceled))
+	_, err = File.BatchHash(ctx, paths, BatchOptions{})
+	require.True(f.T(), errors.Is(err, context.Canceled))
+}
+
+// cancelReader 第一次读取后调用 cancel
+type cancelReader struct {
+	r      io.Reader
+	cancel context.CancelFunc
+}
+
+func (r *cancelReader) Read(p []byte) (int, error) {
+	defer r.cancel()
+	return r.r.Read(p)
+}
+
+func (f *FileBatchTest) TestGopherunFile_BatchRemove() {
+	paths := f.createBatchFiles(30, "abc")
+
+	err := File.BatchRemove(context.Background(), paths, BatchOptions{})
+	require.Nil(f.T(), err)
+	for _, path := range paths {
+		require.NoFileExists(f.T(), path)
+	}
+}
+
+func (f *FileBatchTest) TestGopherunFile_BatchCopy() {
+	paths := f.createBatchFiles(5, "abc")
+	dstDir := filepath.Join(f.T().TempDir(), "nested", "dst")
+
+	files := map[string]string{}
+	for _, path := range paths {
+		files[path] = filepath.Join(dstDir, filepath.Base(path))
+	}
+	files[filepath.Join(dstDir, "not-exist.txt")] = filepath.Join(dstDir, "copy.txt")
+
+	err := File.BatchCopy(context.Background(), files, BatchOptions{Workers: 2})
+	var batchErr *BatchError
+	require.True(f.T(), errors.As(err, &batchErr))
+	require.Len(f.T(), batchErr.Errors, 1)
+
+	for _, path := range paths {
+		content, err := os.ReadFile(filepath.Join(dstDir, filepath.Base(path)))
+		require.Nil(f.T(), err)
+		require.Equal(f.T(), "abc", string(content))
+	}
+}
+
+func (f *FileBatchTest) TestGopherunFile_BatchHash() {
+	paths := f.createBatchFiles(3, "hello")
+
+	hashes, err := File.BatchHash(context.Background(), paths, BatchOptions{})
+	require.Nil(f.T(), err)
+	require.Len(f.T(), hashes, 3)
+	for _, path := range paths {
+		require.Equal(f.T(), "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", hashes[path])
+	}
+}
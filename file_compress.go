@@ -0,0 +1,231 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrCompressionUnsupported 不支持的压缩格式或操作（如标准库没有 bzip2 写入器）
+var ErrCompressionUnsupported = errors.New("unsupported compression")
+
+// Compression 压缩格式
+type Compression uint
+
+// 支持的压缩格式
+const (
+	CompressionNone  Compression = iota // 不压缩
+	CompressionGzip                     // gzip，扩展名 .gz
+	CompressionZlib                     // zlib，扩展名 .zlib
+	CompressionBzip2                    // bzip2，扩展名 .bz2，仅支持读取
+)
+
+// 扩展名与压缩格式的对应关系
+var _compressionExts = map[string]Compression{
+	".gz":   CompressionGzip,
+	".gzip": CompressionGzip,
+	".zlib": CompressionZlib,
+	".bz2":  CompressionBzip2,
+}
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionGzip:
+		return "gzip"
+	case CompressionZlib:
+		return "zlib"
+	case CompressionBzip2:
+		return "bzip2"
+	default:
+		return fmt.Sprintf("Compression(%d)", uint(c))
+	}
+}
+
+// CompressionByExt 根据文件扩展名判断压缩格式，无法识别时返回 CompressionNone
+func (i GopherunFile) CompressionByExt(path string) Compression {
+	return _compressionExts[strings.ToLower(filepath.Ext(path))]
+}
+
+// _zlibSniffSize 根据魔数识别为 zlib 时试解压的字节数
+const _zlibSniffSize = 512
+
+// CompressionByMagic 根据数据开头的魔数判断压缩格式，无法识别时返回 CompressionNone。
+// zlib 头只有两个字节，普通文本也可能符合规则，OpenReader 会额外试解压确认。
+func (i GopherunFile) CompressionByMagic(header []byte) Compression {
+	switch {
+	case len(header) >= 2 && header[0] == 0x1f && header[1] == 0x8b:
+		return CompressionGzip
+	case len(header) >= 3 && header[0] == 'B' && header[1] == 'Z' && header[2] == 'h':
+		return CompressionBzip2
+	case len(header) >= 2 && header[0]&0x0f == 8 && header[0]>>4 <= 7 && header[1]&0x20 == 0 &&
+		(uint16(header[0])<<8|uint16(header[1]))%31 == 0:
+		// zlib 头：CM=8（deflate），CINFO<=7，未设置 FDICT（预设字典），且前两个字节组成的整数是 31 的倍数
+		return CompressionZlib
+	default:
+		return CompressionNone
+	}
+}
+
+// NewDecompressReader 使用指定的压缩格式包装 r
+func (i GopherunFile) NewDecompressReader(r io.Reader, compression Compression) (io.ReadCloser, error) {
+	switch compression {
+	case CompressionNone:
+		return io.NopCloser(r), nil
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	case CompressionZlib:
+		return zlib.NewReader(r)
+	case CompressionBzip2:
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrCompressionUnsupported, compression)
+	}
+}
+
+// NewCompressWriter 使用指定的压缩格式包装 w，调用方必须 Close 返回的 Writer 以写出尾部数据（不会关闭 w）
+func (i GopherunFile) NewCompressWriter(w io.Writer, compression Compression) (io.WriteCloser, error) {
+	switch compression {
+	case CompressionNone:
+		return nopWriteCloser{w}, nil
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZlib:
+		return zlib.NewWriter(w), nil
+	default:
+		return nil, fmt.Errorf("%w: cannot write %s", ErrCompressionUnsupported, compression)
+	}
+}
+
+// OpenReader 打开文件并返回解压后的数据流。
+// 优先根据扩展名判断压缩格式，扩展名无法识别时根据文件开头的魔数判断，
+// 魔数符合 zlib 但无法解压的文件按未压缩处理。
+func (i GopherunFile) OpenReader(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReader(f)
+	compression := i.CompressionByExt(path)
+	if compression == CompressionNone {
+		header, _ := buffered.Peek(3)
+		compression = i.CompressionByMagic(header)
+		if compression == CompressionZlib && !sniffZlib(buffered) {
+			compression = CompressionNone
+		}
+	}
+
+	reader, err := i.NewDecompressReader(buffered, compression)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &multiCloser{Reader: reader, closers: []io.Closer{reader, f}}, nil
+}
+
+// ReadAll 读取文件的全部内容，压缩文件会被透明解压
+func (i GopherunFile) ReadAll(path string) ([]byte, error) {
+	reader, err := i.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// WriteFileSaferCompressed 根据扩展名压缩数据后调用 WriteFileSafer 原子写入，扩展名无法识别时不压缩
+func (i GopherunFile) WriteFileSaferCompressed(writePath string, data []byte, perm os.FileMode) error {
+	compressed, err := i.Compress(data, i.CompressionByExt(writePath))
+	if err != nil {
+		return err
+	}
+	return i.WriteFileSafer(writePath, compressed, perm)
+}
+
+// Compress 使用指定的压缩格式压缩数据
+func (i GopherunFile) Compress(data []byte, compression Compression) ([]byte, error) {
+	if compression == CompressionNone {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	writer, err := i.NewCompressWriter(&buf, compression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress 使用指定的压缩格式解压数据
+func (i GopherunFile) Decompress(data []byte, compression Compression) ([]byte, error) {
+	reader, err := i.NewDecompressReader(bytes.NewReader(data), compression)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// sniffZlib 试解压 r 开头的数据（不消耗 r）；只读取了文件的一部分时，数据被截断不视为错误
+func sniffZlib(r *bufio.Reader) bool {
+	header, _ := r.Peek(_zlibSniffSize)
+	reader, err := zlib.NewReader(bytes.NewReader(header))
+	if err != nil {
+		return false
+	}
+	_, err = io.Copy(io.Discard, reader)
+	return err == nil || (err == io.ErrUnexpectedEOF && len(header) == _zlibSniffSize)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// multiCloser 读取 Reader，关闭时依次关闭所有 closers 并返回第一个错误
+type multiCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *multiCloser) Close() (err error) {
+	for _, closer := range m.closers {
+		if closeErr := closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return
+}
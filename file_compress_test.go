@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type FileCompressTest struct {
+	BaseTest
+}
+
+func TestFileCompressTest(t *testing.T) {
+	suite.Run(t, new(FileCompressTest))
+}
+
+// "hello bzip2" 的 bzip2 压缩数据
+var _bzip2Fixture = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x55, 0x5a,
+	0x44, 0xf7, 0x00, 0x00, 0x02, 0x19, 0x80, 0x40, 0x00, 0x10, 0x00, 0x12,
+	0x64, 0xc0, 0x10, 0x20, 0x00, 0x22, 0x00, 0x69, 0xea, 0x10, 0x03, 0x05,
+	0xd3, 0xb6, 0x21, 0x83, 0xc5, 0xdc, 0x91, 0x4e, 0x14, 0x24, 0x15, 0x56,
+	0x91, 0x3d, 0xc0,
+}
+
+func (f *FileCompressTest) TestGopherunFile_CompressionByExt() {
+	require.Equal(f.T(), CompressionGzip, File.CompressionByExt("data.json.gz"))
+	require.Equal(f.T(), CompressionGzip, File.CompressionByExt("DATA.GZ"))
+	require.Equal(f.T(), CompressionZlib, File.CompressionByExt("data.zlib"))
+	require.Equal(f.T(), CompressionBzip2, File.CompressionByExt("data.bz2"))
+	require.Equal(f.T(), CompressionNone, File.CompressionByExt("data.json"))
+}
+
+func (f *FileCompressTest) TestGopherunFile_CompressionByMagic() {
+	gz, err := File.Compress([]byte("x"), CompressionGzip)
+	require.Nil(f.T(), err)
+	zl, err := File.Compress([]byte("x"), CompressionZlib)
+	require.Nil(f.T(), err)
+
+	require.Equal(f.T(), CompressionGzip, File.CompressionByMagic(gz))
+	require.Equal(f.T(), CompressionZlib, File.CompressionByMagic(zl))
+	require.Equal(f.T(), CompressionBzip2, File.CompressionByMagic(_bzip2Fixture))
+	require.Equal(f.T(), CompressionNone, File.CompressionByMagic([]byte(`{"a":1}`)))
+	require.Equal(f.T(), CompressionNone, File.CompressionByMagic(nil))
+
+	// 设置了 FDICT 的文本不是 zlib
+	require.Equal(f.T(), CompressionNone, File.CompressionByMagic([]byte("80,90\n")))
+	require.Equal(f.T(), CompressionNone, File.CompressionByMagic([]byte("x hello")))
+}
+
+func (f *FileCompressTest) TestGopherunFile_ReadAll_case1() {
+	tempDir := f.T().TempDir()
+	data := []byte(`{"name":"zhangsan"}`)
+
+	// 按扩展名压缩写入后再透明读取
+	for _, name := range []string{"data.json", "data.json.gz", "data.json.zlib"} {
+		path := filepath.Join(tempDir, name)
+		require.Nil(f.T(), File.WriteFileSaferCompressed(path, data, 0644))
+
+		raw, err := os.ReadFile(path)
+		require.Nil(f.T(), err)
+		require.Equal(f.T(), name == "data.json", string(raw) == string(data), name)
+
+		content, err := File.ReadAll(path)
+		require.Nil(f.T(), err)
+		require.Equal(f.T(), data, content, name)
+	}
+}
+
+func (f *FileCompressTest) TestGopherunFile_ReadAll_case2() {
+	tempDir := f.T().TempDir()
+
+	// 扩展名无法识别时根据魔数判断
+	gz, err := File.Compress([]byte("hello gzip"), CompressionGzip)
+	require.Nil(f.T(), err)
+	require.Nil(f.T(), os.WriteFile(filepath.Join(tempDir, "export.bin"), gz, 0644))
+	content, err := File.ReadAll(filepath.Join(tempDir, "export.bin"))
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), "hello gzip", string(content))
+
+	require.Nil(f.T(), os.WriteFile(filepath.Join(tempDir, "export.bz2"), _bzip2Fixture, 0644))
+	content, err = File.ReadAll(filepath.Join(tempDir, "export.bz2"))
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), "hello bzip2", string(content))
+
+	// 扩展名与内容不符
+	require.Nil(f.T(), os.WriteFile(filepath.Join(tempDir, "plain.gz"), []byte("plain"), 0644))
+	_, err = File.ReadAll(filepath.Join(tempDir, "plain.gz"))
+	require.NotNil(f.T(), err)
+
+	_, err = File.ReadAll(filepath.Join(tempDir, "not-exist.gz"))
+	require.True(f.T(), os.IsNotExist(err))
+}
+
+func (f *FileCompressTest) TestGopherunFile_ReadAll_case3() {
+	tempDir := f.T().TempDir()
+
+	// 开头恰好符合 zlib 头规则的文本按原样读取
+	for _, text := range []string{"80,90\n", "x hello", "x^abc\n"} {
+		path := filepath.Join(tempDir, "scores.txt")
+		require.Nil(f.T(), os.WriteFile(path, []byte(text), 0644))
+		content, err := File.ReadAll(path)
+		require.Nil(f.T(), err, text)
+		require.Equal(f.T(), text, string(content))
+	}
+
+	// 没有 .zlib 扩展名的 zlib 数据仍按魔数解压
+	zl, err := File.Compress([]byte("hello zlib"), CompressionZlib)
+	require.Nil(f.T(), err)
+	require.Nil(f.T(), os.WriteFile(filepath.Join(tempDir, "export.bin"), zl, 0644))
+	content, err := File.ReadAll(filepath.Join(tempDir, "export.bin"))
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), "hello zlib", string(content))
+
+	// 压缩后超过试解压长度的数据
+	large := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(large)
+	zl, err = File.Compress(large, CompressionZlib)
+	require.Nil(f.T(), err)
+	require.Greater(f.T(), len(zl), _zlibSniffSize)
+	require.Nil(f.T(), os.WriteFile(filepath.Join(tempDir, "large.bin"), zl, 0644))
+	content, err = File.ReadAll(filepath.Join(tempDir, "large.bin"))
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), large, content)
+}
+
+func (f *FileCompressTest) TestGopherunFile_WriteFileSaferCompressed() {
+	tempDir := f.T().TempDir()
+
+	err := File.WriteFileSaferCompressed(filepath.Join(tempDir, "data.bz2"), []byte("data"), 0644)
+	require.True(f.T(), errors.Is(err, ErrCompressionUnsupported))
+	require.NoFileExists(f.T(), filepath.Join(tempDir, "data.bz2"))
+}
+
+func (f *FileCompressTest) TestGopherunFile_Decompress() {
+	content, err := File.Decompress(_bzip2Fixture, CompressionBzip2)
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), "hello bzip2", string(content))
+
+	_, err = File.Decompress(nil, Compression(99))
+	require.True(f.T(), errors.Is(err, ErrCompressionUnsupported))
+	require.Equal(f.T(), "Compression(99)", Compression(99).String())
+}
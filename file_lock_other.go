@@ -0,0 +1,30 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd || solaris || aix || windows)
+
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// tryLockFile 当前平台不支持文件锁，返回 ErrFileLockUnsupported 而不是假装加锁成功
+func tryLockFile(f *os.File) (bool, error) {
+	return false, fmt.Errorf("%w: %s", ErrFileLockUnsupported, runtime.GOOS)
+}
+
+func unlockFile(f *os.File) error {
+	return fmt.Errorf("%w: %s", ErrFileLockUnsupported, runtime.GOOS)
+}
@@ -0,0 +1,56 @@
+//go:build windows
+
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	_lockfileFailImmediately = 0x00000001
+	_lockfileExclusiveLock   = 0x00000002
+	_errorLockViolation      = syscall.Errno(33)
+)
+
+var (
+	_kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	_procLockFileEx   = _kernel32.NewProc("LockFileEx")
+	_procUnlockFileEx = _kernel32.NewProc("UnlockFileEx")
+)
+
+// tryLockFile 以非阻塞方式获取 f 的排他锁，锁被其他文件句柄持有时返回 false
+func tryLockFile(f *os.File) (bool, error) {
+	var overlapped syscall.Overlapped
+	r, _, err := _procLockFileEx.Call(f.Fd(), _lockfileExclusiveLock|_lockfileFailImmediately,
+		0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if r != 0 {
+		return true, nil
+	}
+	if err == _errorLockViolation {
+		return false, nil
+	}
+	return false, err
+}
+
+func unlockFile(f *os.File) error {
+	var overlapped syscall.Overlapped
+	r, _, err := _procUnlockFileEx.Call(f.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrPathEscape 目标路径逃逸出基准目录
+var ErrPathEscape = errors.New("path escapes from base directory")
+
+// 文件名最大字节数（大多数文件系统的限制）
+const _maxFilenameBytes = 255
+
+// UniqueName 最多尝试的序号
+const _maxUniqueNameAttempts = 10000
+
+// Windows 保留设备名
+var _reservedFilenames = map[string]struct{}{
+	"CON": {}, "PRN": {}, "AUX": {}, "NUL": {},
+	"COM1": {}, "COM2": {}, "COM3": {}, "COM4": {}, "COM5": {}, "COM6": {}, "COM7": {}, "COM8": {}, "COM9": {},
+	"LPT1": {}, "LPT2": {}, "LPT3": {}, "LPT4": {}, "LPT5": {}, "LPT6": {}, "LPT7": {}, "LPT8": {}, "LPT9": {},
+}
+
+// ExpandHome 将路径开头的 ~ 或 ~user 展开为对应用户的主目录
+func (i GopherunFile) ExpandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+
+	// 拆分出 ~user 部分与剩余路径
+	name, rest := path[1:], ""
+	if idx := strings.IndexAny(name, `/\`); idx >= 0 {
+		name, rest = name[:idx], name[idx:]
+	}
+
+	var home string
+	if name == "" {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		home = dir
+	} else {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", err
+		}
+		home = u.HomeDir
+	}
+	return home + rest, nil
+}
+
+// ExpandEnv 展开路径中的 $VAR 或 ${VAR} 环境变量，未定义的变量替换为空字符串
+func (i GopherunFile) ExpandEnv(path string) string {
+	return os.ExpandEnv(path)
+}
+
+// NormalizePath 依次展开 ~、环境变量，并返回清理后的绝对路径
+func (i GopherunFile) NormalizePath(path string) (string, error) {
+	expanded, err := i.ExpandHome(i.ExpandEnv(path))
+	if err != nil {
+		return "", err
+	}
+	return i.GetAbsolutePath(expanded)
+}
+
+// Rel 返回 targetPath 相对于 basePath 的路径，若结果逃逸出 basePath 则返回 ErrPathEscape
+func (i GopherunFile) Rel(basePath, targetPath string) (string, error) {
+	rel, err := filepath.Rel(basePath, targetPath)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s is outside %s", ErrPathEscape, targetPath, basePath)
+	}
+	return rel, nil
+}
+
+// IsSubPath 判断 child 是否位于 parent 目录内（含 parent 自身），判断前会解析符号链接
+func (i GopherunFile) IsSubPath(parent, child string) (bool, error) {
+	resolvedParent, err := i.resolvePath(parent)
+	if err != nil {
+		return false, err
+	}
+	resolvedChild, err := i.resolvePath(child)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err = i.Rel(resolvedParent, resolvedChild); err != nil {
+		if errors.Is(err, ErrPathEscape) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// resolvePath 返回绝对路径，并解析其中已存在部分的符号链接（路径可以尚不存在）
+func (i GopherunFile) resolvePath(path string) (string, error) {
+	absPath, err := i.GetAbsolutePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	// 自底向上找到第一个已存在的祖先目录，解析后再拼回不存在的部分
+	existing, missing := absPath, ""
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			return filepath.Join(resolved, missing), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return absPath, nil
+		}
+		missing = filepath.Join(filepath.Base(existing), missing)
+		existing = parent
+	}
+}
+
+// SanitizeFilename 将任意字符串转换为可跨平台使用的文件名：
+// 替换保留字符与控制字符、去除结尾的空格和点、规避 Windows 保留设备名，并限制长度不超过 255 字节。
+func (i GopherunFile) SanitizeFilename(name string) string {
+	var builder strings.Builder
+	for _, r := range name {
+		switch {
+		case r < 0x20 || r == 0x7f || r == utf8.RuneError:
+			builder.WriteRune('_')
+		case strings.ContainsRune(`<>:"/\|?*`, r):
+			builder.WriteRune('_')
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	// Windows 不允许文件名以空格或点结尾
+	result := strings.TrimRight(builder.String(), " .")
+	if result == "" {
+		return "_"
+	}
+
+	// 保留设备名不区分大小写，且带扩展名同样被保留（如 CON.txt）
+	stem := result
+	if idx := strings.IndexByte(stem, '.'); idx >= 0 {
+		stem = stem[:idx]
+	}
+	if _, ok := _reservedFilenames[strings.ToUpper(stem)]; ok {
+		result = "_" + result
+	}
+
+	return truncateFilename(result, _maxFilenameBytes)
+}
+
+// truncateFilename 在保留扩展名的前提下按 UTF-8 边界截断文件名
+func truncateFilename(name string, maxBytes int) string {
+	if len(name) <= maxBytes {
+		return name
+	}
+
+	ext := filepath.Ext(name)
+	if len(ext) >= maxBytes {
+		ext = ""
+	}
+	stem := name[:len(name)-len(ext)]
+	limit := maxBytes - len(ext)
+	for limit > 0 && !utf8.RuneStart(stem[limit]) {
+		limit--
+	}
+	return stem[:limit] + ext
+}
+
+// UniqueName 在 dir 目录下以 base 为名创建一个新的空文件并返回其完整路径；
+// 若同名文件已存在，则依次尝试 "name (1).ext"、"name (2).ext" ……
+// 文件使用 O_EXCL 创建，因此并发调用不会得到相同的文件名。
+func (i GopherunFile) UniqueName(dir, base string) (string, error) {
+	ext := filepath.Ext(base)
+	if ext == base {
+		// 隐藏文件（如 .bashrc）整体作为文件名，得到 ".bashrc (1)"
+		ext = ""
+	}
+	stem := strings.TrimSuffix(base, ext)
+
+	for n := 0; n < _maxUniqueNameAttempts; n++ {
+		name := base
+		if n > 0 {
+			name = fmt.Sprintf("%s (%d)%s", stem, n, ext)
+		}
+		path := filepath.Join(dir, name)
+
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+		if err == nil {
+			return path, f.Close()
+		}
+		if !os.IsExist(err) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no unique name available for %s in %s", base, dir)
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type FilePathTest struct {
+	BaseTest
+}
+
+func TestFilePathTest(t *testing.T) {
+	suite.Run(t, new(FilePathTest))
+}
+
+func (f *FilePathTest) TestGopherunFile_ExpandHome() {
+	home, err := os.UserHomeDir()
+	require.Nil(f.T(), err)
+
+	path, err := File.ExpandHome("~")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), home, path)
+
+	path, err = File.ExpandHome("~/a/b")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), home+"/a/b", path)
+
+	path, err = File.ExpandHome("/a/~/b")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), "/a/~/b", path)
+
+	_, err = File.ExpandHome("~no-such-user-gopherun/a")
+	require.NotNil(f.T(), err)
+}
+
+func (f *FilePathTest) TestGopherunFile_NormalizePath() {
+	tempDir := f.T().TempDir()
+	f.T().Setenv("GOPHERUN_TEST_DIR", tempDir)
+
+	path, err := File.NormalizePath("$GOPHERUN_TEST_DIR/a/../b")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), filepath.Join(tempDir, "b"), path)
+}
+
+func (f *FilePathTest) TestGopherunFile_Rel() {
+	rel, err := File.Rel("/a/b", "/a/b/c/d")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), filepath.Join("c", "d"), rel)
+
+	rel, err = File.Rel("/a/b", "/a/b/..c")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), "..c", rel)
+
+	_, err = File.Rel("/a/b", "/a/c")
+	require.True(f.T(), errors.Is(err, ErrPathEscape))
+
+	_, err = File.Rel("/a/b", "/a")
+	require.True(f.T(), errors.Is(err, ErrPathEscape))
+}
+
+func (f *FilePathTest) TestGopherunFile_IsSubPath() {
+	tempDir := f.T().TempDir()
+	parent := filepath.Join(tempDir, "parent")
+	outside := filepath.Join(tempDir, "outside")
+	require.Nil(f.T(), File.MkdirAll(filepath.Join(parent, "child")))
+	require.Nil(f.T(), File.MkdirAll(outside))
+
+	ok, err := File.IsSubPath(parent, filepath.Join(parent, "child", "not-exist.txt"))
+	require.Nil(f.T(), err)
+	require.True(f.T(), ok)
+
+	ok, err = File.IsSubPath(parent, parent)
+	require.Nil(f.T(), err)
+	require.True(f.T(), ok)
+
+	ok, err = File.IsSubPath(parent, outside)
+	require.Nil(f.T(), err)
+	require.False(f.T(), ok)
+
+	// 指向外部的符号链接不属于 parent
+	link := filepath.Join(parent, "link")
+	require.Nil(f.T(), os.Symlink(outside, link))
+	ok, err = File.IsSubPath(parent, filepath.Join(link, "file.txt"))
+	require.Nil(f.T(), err)
+	require.False(f.T(), ok)
+}
+
+func (f *FilePathTest) TestGopherunFile_SanitizeFilename() {
+	require.Equal(f.T(), "a_b_c_.txt", File.SanitizeFilename("a/b\\c?.txt"))
+	require.Equal(f.T(), "name", File.SanitizeFilename("name. . "))
+	require.Equal(f.T(), "_", File.SanitizeFilename(""))
+	require.Equal(f.T(), "_", File.SanitizeFilename("..."))
+	require.Equal(f.T(), "_CON", File.SanitizeFilename("CON"))
+	require.Equal(f.T(), "_com1.txt", File.SanitizeFilename("com1.txt"))
+	require.Equal(f.T(), "a_b", File.SanitizeFilename("a\x00b"))
+
+	long := File.SanitizeFilename(strings.Repeat("文", 200) + ".json")
+	require.True(f.T(), len(long) <= 255)
+	require.True(f.T(), strings.HasSuffix(long, ".json"))
+	require.True(f.T(), strings.HasPrefix(long, "文"))
+}
+
+func (f *FilePathTest) TestGopherunFile_UniqueName_case1() {
+	tempDir := f.T().TempDir()
+
+	path, err := File.UniqueName(tempDir, "file.txt")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), filepath.Join(tempDir, "file.txt"), path)
+
+	path, err = File.UniqueName(tempDir, "file.txt")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), filepath.Join(tempDir, "file (1).txt"), path)
+
+	path, err = File.UniqueName(tempDir, "file.txt")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), filepath.Join(tempDir, "file (2).txt"), path)
+}
+
+func (f *FilePathTest) TestGopherunFile_UniqueName_case2() {
+	tempDir := f.T().TempDir()
+
+	// 并发获取的文件名互不相同
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		names = map[string]struct{}{}
+	)
+	for n := 0; n < 20; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			path, err := File.UniqueName(tempDir, "report.csv")
+			require.Nil(f.T(), err)
+			mu.Lock()
+			names[path] = struct{}{}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	require.Len(f.T(), names, 20)
+
+	_, err := File.UniqueName(filepath.Join(tempDir, "not-exist"), "a.txt")
+	require.NotNil(f.T(), err)
+}
+
+func (f *FilePathTest) TestGopherunFile_UniqueName_case3() {
+	tempDir := f.T().TempDir()
+
+	// 隐藏文件没有扩展名
+	path, err := File.UniqueName(tempDir, ".bashrc")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), filepath.Join(tempDir, ".bashrc"), path)
+
+	path, err = File.UniqueName(tempDir, ".bashrc")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), filepath.Join(tempDir, ".bashrc (1)"), path)
+
+	// 带扩展名的隐藏文件
+	_, err = File.UniqueName(tempDir, ".env.local")
+	require.Nil(f.T(), err)
+	path, err = File.UniqueName(tempDir, ".env.local")
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), filepath.Join(tempDir, ".env (1).local"), path)
+}
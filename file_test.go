@@ -375,3 +375,57 @@ func (f *FileTest) TestGopherunFile_WriteFileSafer_case8() {
 	require.Truef(f.T(), err != nil, "WriteFileSafer err, %s", tempDir)
 	require.NoFileExists(f.T(), "student.txt")
 }
+
+func (f *FileTest) TestGopherunFile_CopyFile() {
+	tempDir := f.T().TempDir()
+	src := filepath.Join(tempDir, "student.txt")
+	dst := filepath.Join(tempDir, "student.txt.bak")
+
+	err := File.WriteFileSafer(src, []byte("zhangsan"), 0640)
+	require.Truef(f.T(), err == nil, "WriteFileSafer err, %s", tempDir)
+
+	written, err := File.CopyFile(src, dst)
+	require.Truef(f.T(), err == nil, "CopyFile err, %v", err)
+	require.True(f.T(), written == int64(len("zhangsan")))
+
+	content, err := os.ReadFile(dst)
+	require.True(f.T(), err == nil)
+	require.True(f.T(), string(content) == "zhangsan")
+
+	stat, err := os.Stat(dst)
+	require.True(f.T(), err == nil)
+	require.True(f.T(), stat.Mode().Perm() == 0640, "Mode should be 0640", stat.Mode())
+
+	_, err = File.CopyFile(filepath.Join(tempDir, "not-exist.txt"), dst)
+	require.True(f.T(), err != nil)
+
+	// 已存在的 dst 同样设置为 src 的权限
+	require.Nil(f.T(), os.Chmod(dst, 0600))
+	require.Nil(f.T(), os.Chmod(src, 0604))
+	_, err = File.CopyFile(src, dst)
+	require.Nil(f.T(), err)
+	stat, err = os.Stat(dst)
+	require.Nil(f.T(), err)
+	require.Equal(f.T(), os.FileMode(0604), stat.Mode().Perm())
+}
+
+func (f *FileTest) TestGopherunFile_CopyFile_sameFile() {
+	tempDir := f.T().TempDir()
+	src := filepath.Join(tempDir, "student.txt")
+	require.Nil(f.T(), os.WriteFile(src, []byte("zhangsan"), 0644))
+
+	targets := []string{src, filepath.Join(tempDir, ".", "student.txt")}
+	if link := filepath.Join(tempDir, "hardlink.txt"); os.Link(src, link) == nil {
+		targets = append(targets, link)
+	}
+	if link := filepath.Join(tempDir, "symlink.txt"); os.Symlink(src, link) == nil {
+		targets = append(targets, link)
+	}
+	for _, dst := range targets {
+		_, err := File.CopyFile(src, dst)
+		require.True(f.T(), errors.Is(err, ErrSameFile), "%s: %v", dst, err)
+		content, err := os.ReadFile(src)
+		require.Nil(f.T(), err)
+		require.Equal(f.T(), "zhangsan", string(content), dst)
+	}
+}
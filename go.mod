@@ -2,10 +2,13 @@ module github.com/tootscharlie/gopherun
 
 go 1.19
 
-require github.com/stretchr/testify v1.10.0
+require (
+	github.com/agiledragon/gomonkey/v2 v2.12.0
+	github.com/goccy/go-json v0.10.2
+	github.com/stretchr/testify v1.10.0
+)
 
 require (
-	github.com/agiledragon/gomonkey/v2 v2.12.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
@@ -10,10 +10,26 @@
 
 package gopherun
 
-import "encoding/json"
+import (
+	"bytes"
+	"reflect"
+)
 
 func (i GopherunJSON) Encode(obj interface{}) ([]byte, error) {
-	return json.Marshal(obj)
+	data, err := i.Codec().Marshal(obj)
+	if err != nil || _jsonTypes.empty() || !hasJSONDiscriminators(reflect.ValueOf(obj)) {
+		return data, err
+	}
+
+	// 为 JSONRegisterType 注册的具体类型写入区分字段
+	tree, err := decodeOrderedJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	addJSONDiscriminators(tree, reflect.ValueOf(obj), map[reflect.Type]map[string][]int{})
+	var buf bytes.Buffer
+	writeOrderedJSON(&buf, tree, true)
+	return buf.Bytes(), nil
 }
 
 func (i GopherunJSON) EncodeToJSONStr(obj interface{}) (jsonStr string, err error) {
@@ -24,8 +40,16 @@ func (i GopherunJSON) EncodeToJSONStr(obj interface{}) (jsonStr string, err erro
 	return string(bytes), nil
 }
 
+// Decode 将 bytes 解码到 obj，语法错误与类型错误会包装为 *JSONDecodeError，给出行列号、JSON Pointer 与出错片段；
+// obj 中包含 JSONRegisterType 注册的接口类型时按区分字段选择具体类型
 func (i GopherunJSON) Decode(bytes []byte, obj interface{}) error {
-	return json.Unmarshal(bytes, obj)
+	if decodesJSONUnions(obj) {
+		return decodeJSONUnions(bytes, 0, len(bytes), "", obj, i.Codec().Unmarshal)
+	}
+	if err := i.Codec().Unmarshal(bytes, obj); err != nil {
+		return newJSONDecodeError(bytes, err)
+	}
+	return nil
 }
 
 func (i GopherunJSON) DecodeByJSONStr(jsonStr string, obj interface{}) error {
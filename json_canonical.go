@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// ErrNotCanonicalizable 值无法按 RFC 8785 规范化（如重复的键、超出 IEEE 754 双精度范围的数字）
+var ErrNotCanonicalizable = errors.New("JSON value cannot be canonicalized")
+
+// EncodeCanonical 按 RFC 8785（JSON Canonicalization Scheme）输出规范化 JSON，相同的数据在任何服务上都得到相同的字节：
+// 对象键按 UTF-16 码元排序，数字按 ECMAScript 规则格式化（1.0 输出为 1，1e21 输出为 1e+21），
+// 字符串只转义必须转义的字符，且没有任何空白。
+// obj 可以是原始 JSON（[]byte 或 json.RawMessage），也可以是任意可编码的 Go 值。
+// 数字按双精度浮点数处理，超过 2^53 的整数会丢失精度，需要精确传递时应编码为字符串。
+func (i GopherunJSON) EncodeCanonical(obj interface{}) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch raw := obj.(type) {
+	case []byte:
+		data = raw
+	case json.RawMessage:
+		data = raw
+	default:
+		if data, err = i.Encode(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	tree, err := decodeOrderedJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = writeCanonicalJSON(&buf, tree); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CanonicalSHA256 返回 obj 规范化 JSON 的 SHA-256 摘要（十六进制小写），可用于签名与去重
+func (i GopherunJSON) CanonicalSHA256(obj interface{}) (string, error) {
+	data, err := i.EncodeCanonical(obj)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func writeCanonicalJSON(buf *bytes.Buffer, node interface{}) error {
+	switch value := node.(type) {
+	case *orderedJSONObject:
+		indexes := make([]int, len(value.keys))
+		keys := make([][]uint16, len(value.keys))
+		for idx, key := range value.keys {
+			indexes[idx] = idx
+			keys[idx] = utf16.Encode([]rune(key))
+		}
+		sort.Slice(indexes, func(a, b int) bool {
+			return compareUTF16(keys[indexes[a]], keys[indexes[b]]) < 0
+		})
+
+		buf.WriteByte('{')
+		for pos, idx := range indexes {
+			if pos > 0 {
+				if value.keys[idx] == value.keys[indexes[pos-1]] {
+					return fmt.Errorf("%w: duplicate key %q", ErrNotCanonicalizable, value.keys[idx])
+				}
+				buf.WriteByte(',')
+			}
+			writeCanonicalJSONString(buf, value.keys[idx])
+			buf.WriteByte(':')
+			if err := writeCanonicalJSON(buf, value.values[idx]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for idx, item := range value {
+			if idx > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case string:
+		writeCanonicalJSONString(buf, value)
+	case json.Number:
+		number, err := formatCanonicalNumber(value)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(number)
+	case bool:
+		buf.WriteString(strconv.FormatBool(value))
+	default:
+		buf.WriteString("null")
+	}
+	return nil
+}
+
+func compareUTF16(a, b []uint16) int {
+	for idx := 0; idx < len(a) && idx < len(b); idx++ {
+		if a[idx] != b[idx] {
+			if a[idx] < b[idx] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// writeCanonicalJSONString 只转义引号、反斜杠与控制字符，其余字符（包括非 ASCII）原样输出
+func writeCanonicalJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(_hexDigits[r>>4])
+				buf.WriteByte(_hexDigits[r&0xF])
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// formatCanonicalNumber 按 ECMAScript Number.prototype.toString 的规则格式化双精度浮点数
+func formatCanonicalNumber(number json.Number) (string, error) {
+	f, err := strconv.ParseFloat(number.String(), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", fmt.Errorf("%w: number %s is out of range", ErrNotCanonicalizable, number)
+	}
+	if f == 0 {
+		return "0", nil
+	}
+
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// 最短的可还原表示：digits 为有效数字，小数点位于第 point 位之后
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	exp, _ := strconv.Atoi(exponent)
+	point := exp + 1
+
+	var text string
+	switch {
+	case len(digits) <= point && point <= 21:
+		text = digits + strings.Repeat("0", point-len(digits))
+	case 0 < point && point <= 21:
+		text = digits[:point] + "." + digits[point:]
+	case -6 < point && point <= 0:
+		text = "0." + strings.Repeat("0", -point) + digits
+	default:
+		expSign := "+"
+		if point-1 < 0 {
+			expSign = "-"
+		}
+		expAbs := point - 1
+		if expAbs < 0 {
+			expAbs = -expAbs
+		}
+		text = digits[:1]
+		if len(digits) > 1 {
+			text += "." + digits[1:]
+		}
+		text += "e" + expSign + strconv.Itoa(expAbs)
+	}
+	return sign + text, nil
+}
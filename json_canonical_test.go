@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"math"
+	"strconv"
+	"testing"
+)
+
+type JSONCanonicalTest struct {
+	suite.Suite
+}
+
+func TestJSONCanonicalTest(t *testing.T) {
+	suite.Run(t, new(JSONCanonicalTest))
+}
+
+func (j *JSONCanonicalTest) TestGopherunJSON_EncodeCanonical_case1() {
+	// RFC 8785 3.2.4 中的示例
+	input := `{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`
+	data, err := JSON.EncodeCanonical([]byte(input))
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`, string(data))
+
+	// 按 UTF-16 码元排序（RFC 8785 3.2.3）
+	input = `{"\u20ac":"Euro Sign","\r":"Carriage Return","\ufb33":"Hebrew Letter Dalet With Dagesh","1":"One","\ud83d\ude00":"Emoji: Grinning Face","\u0080":"Control","\u00f6":"Latin Small Letter O With Diaeresis"}`
+	data, err = JSON.EncodeCanonical(json.RawMessage(input))
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"ö\":\"Latin Small Letter O With Diaeresis\",\"€\":\"Euro Sign\",\"😀\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}", string(data))
+
+	// Go 值：HTML 字符不转义，键排序
+	type User struct {
+		Name string  `json:"name"`
+		Age  int     `json:"age"`
+		Rate float64 `json:"rate"`
+		Bio  string  `json:"bio"`
+	}
+	data, err = JSON.EncodeCanonical(User{Name: "zhangsan", Age: 12, Rate: 1.0, Bio: "<a&b>"})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"age":12,"bio":"<a&b>","name":"zhangsan","rate":1}`, string(data))
+
+	// 格式、键顺序不同但语义相同的文档得到相同结果
+	a, err := JSON.EncodeCanonical([]byte(`{ "b" : [1.0, 2e0], "a" : {"y":1,"x":-0} }`))
+	require.Nil(j.T(), err)
+	b, err := JSON.EncodeCanonical([]byte(`{"a":{"x":0,"y":1.00},"b":[1,2]}`))
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), string(a), string(b))
+	require.Equal(j.T(), `{"a":{"x":0,"y":1},"b":[1,2]}`, string(a))
+}
+
+func (j *JSONCanonicalTest) TestGopherunJSON_EncodeCanonical_case2() {
+	// RFC 8785 附录 B 中的数字格式化样例
+	cases := []struct {
+		bits     uint64
+		expected string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	}
+	for _, c := range cases {
+		input := strconv.FormatFloat(math.Float64frombits(c.bits), 'g', -1, 64)
+		data, err := JSON.EncodeCanonical([]byte(input))
+		require.Nil(j.T(), err, input)
+		require.Equal(j.T(), c.expected, string(data), input)
+	}
+
+	data, err := JSON.EncodeCanonical([]byte(`[9007199254740993, 1.5e300, 123e-2, 100]`))
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `[9007199254740992,1.5e+300,1.23,100]`, string(data))
+}
+
+func (j *JSONCanonicalTest) TestGopherunJSON_EncodeCanonical_case3() {
+	_, err := JSON.EncodeCanonical([]byte(`{"a":1,"a":2}`))
+	require.True(j.T(), errors.Is(err, ErrNotCanonicalizable))
+	_, err = JSON.EncodeCanonical([]byte(`[{"x":{"b":1,"b":1}}]`))
+	require.True(j.T(), errors.Is(err, ErrNotCanonicalizable))
+	_, err = JSON.EncodeCanonical([]byte(`1e400`))
+	require.True(j.T(), errors.Is(err, ErrNotCanonicalizable))
+
+	_, err = JSON.EncodeCanonical([]byte(`{`))
+	require.NotNil(j.T(), err)
+	_, err = JSON.EncodeCanonical([]byte(`{} {}`))
+	require.NotNil(j.T(), err)
+	_, err = JSON.EncodeCanonical(make(chan int))
+	require.NotNil(j.T(), err)
+}
+
+func (j *JSONCanonicalTest) TestGopherunJSON_CanonicalSHA256() {
+	hash, err := JSON.CanonicalSHA256([]byte(`{"b":2,"a":1}`))
+	require.Nil(j.T(), err)
+	// sha256 of `{"a":1,"b":2}`
+	require.Equal(j.T(), "43258cff783fe7036d8a43033f830adfc60ec037382473548ac742b888292777", hash)
+
+	other, err := JSON.CanonicalSHA256(map[string]int{"a": 1, "b": 2})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), hash, other)
+
+	other, err = JSON.CanonicalSHA256(map[string]int{"a": 1, "b": 3})
+	require.Nil(j.T(), err)
+	require.NotEqual(j.T(), hash, other)
+
+	_, err = JSON.CanonicalSHA256([]byte(`{`))
+	require.NotNil(j.T(), err)
+}
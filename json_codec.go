@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+// JSONCodec JSON 编解码引擎，语义需与 encoding/json 的 Marshal、Unmarshal 保持一致。
+// 第三方引擎（如 jsoniter、sonic）通常已提供同名方法，可直接传给 SetCodec。
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StdJSONCodec 基于 encoding/json 的默认引擎
+type StdJSONCodec struct{}
+
+func (StdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (StdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// jsonCodecHolder atomic.Value 要求存入的具体类型一致，因此包装一层
+type jsonCodecHolder struct {
+	codec JSONCodec
+}
+
+var _jsonCodec atomic.Value
+
+func init() {
+	_jsonCodec.Store(jsonCodecHolder{codec: StdJSONCodec{}})
+}
+
+// SetCodec 替换编解码使用的引擎，codec 为 nil 时恢复为 StdJSONCodec。
+// Encode、Decode、EncodeWithOptions、Pretty、DecodeStrict、流式解码中的每条记录、ReadJSONFile、WriteJSONFile
+// 等将值与 JSON 相互转换的功能均使用该引擎，以下情况例外，仍使用 encoding/json：
+//   - DecodeWithOptions 设置了 UseNumber 时（JSONCodec 没有对应的配置）；
+//   - 只处理 JSON 文本而不涉及 Go 类型的操作，如格式化缩进、切分流中的记录、严格模式的预检查、JSON Pointer 等。
+//
+// 应在程序启动时调用；并发调用是安全的，但切换前后的调用可能使用不同的引擎。
+func (i GopherunJSON) SetCodec(codec JSONCodec) {
+	if codec == nil {
+		codec = StdJSONCodec{}
+	}
+	_jsonCodec.Store(jsonCodecHolder{codec: codec})
+}
+
+// Codec 返回当前使用的引擎
+func (i GopherunJSON) Codec() JSONCodec {
+	return _jsonCodec.Load().(jsonCodecHolder).codec
+}
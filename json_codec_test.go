@@ -0,0 +1,263 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	gojson "github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"path/filepath"
+	"testing"
+)
+
+type JSONCodecTest struct {
+	suite.Suite
+}
+
+func TestJSONCodecTest(t *testing.T) {
+	suite.Run(t, new(JSONCodecTest))
+}
+
+func (j *JSONCodecTest) TearDownTest() {
+	JSON.SetCodec(nil)
+}
+
+// countingCodec 统计调用次数并转发给默认引擎
+type countingCodec struct {
+	StdJSONCodec
+	marshals, unmarshals int
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return c.StdJSONCodec.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshals++
+	return c.StdJSONCodec.Unmarshal(data, v)
+}
+
+// rawHTMLCodec 不转义 HTML 字符的引擎
+type rawHTMLCodec struct {
+	StdJSONCodec
+}
+
+func (rawHTMLCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
+// goJSONCodec 基于 github.com/goccy/go-json 的引擎
+type goJSONCodec struct{}
+
+func (goJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return gojson.Marshal(v)
+}
+
+func (goJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return gojson.Unmarshal(data, v)
+}
+
+type failingCodec struct{}
+
+func (failingCodec) Marshal(interface{}) ([]byte, error) {
+	return nil, errors.New("marshal failed")
+}
+
+func (failingCodec) Unmarshal([]byte, interface{}) error {
+	return errors.New("unmarshal failed")
+}
+
+func (j *JSONCodecTest) TestGopherunJSON_SetCodec_case1() {
+	require.Equal(j.T(), StdJSONCodec{}, JSON.Codec())
+
+	codec := &countingCodec{}
+	JSON.SetCodec(codec)
+	require.Equal(j.T(), codec, JSON.Codec())
+
+	jsonStr, err := JSON.EncodeToJSONStr(map[string]int{"a": 1})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"a":1}`, jsonStr)
+
+	var obj map[string]int
+	require.Nil(j.T(), JSON.DecodeByJSONStr(jsonStr, &obj))
+	require.Equal(j.T(), map[string]int{"a": 1}, obj)
+
+	_, err = JSONDecodeAs[map[string]int]([]byte(jsonStr))
+	require.Nil(j.T(), err)
+
+	require.Equal(j.T(), 1, codec.marshals)
+	require.Equal(j.T(), 2, codec.unmarshals)
+
+	// nil 恢复为默认引擎
+	JSON.SetCodec(nil)
+	require.Equal(j.T(), StdJSONCodec{}, JSON.Codec())
+}
+
+func (j *JSONCodecTest) TestGopherunJSON_SetCodec_case2() {
+	JSON.SetCodec(failingCodec{})
+
+	_, err := JSON.Encode(1)
+	require.EqualError(j.T(), err, "marshal failed")
+	_, err = JSON.EncodeToJSONStr(1)
+	require.EqualError(j.T(), err, "marshal failed")
+	require.EqualError(j.T(), JSON.Decode([]byte(`1`), new(int)), "unmarshal failed")
+	require.EqualError(j.T(), JSON.DecodeByJSONStr(`1`, new(int)), "unmarshal failed")
+}
+
+func (j *JSONCodecTest) TestGopherunJSON_SetCodec_case3() {
+	codec := &countingCodec{}
+	JSON.SetCodec(codec)
+
+	// 带选项编码、Pretty、严格解码与文件读写同样使用设置的引擎
+	_, err := JSON.EncodeWithOptions(map[string]int{"a": 1}, JSONEncodeOptions{SortKeys: true})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "{\n  \"a\": 1\n}", JSON.Pretty(map[string]int{"a": 1}))
+	var obj map[string]int
+	require.Nil(j.T(), JSON.DecodeStrictByJSONStr(`{"a":1}`, &obj))
+
+	path := filepath.Join(j.T().TempDir(), "data.json")
+	require.Nil(j.T(), JSON.WriteJSONFile(path, obj, 0644, JSONFileOptions{}))
+	require.Nil(j.T(), JSON.ReadJSONFile(path, &obj))
+
+	require.Equal(j.T(), 3, codec.marshals)
+	require.Equal(j.T(), 2, codec.unmarshals)
+
+	JSON.SetCodec(failingCodec{})
+	_, err = JSON.EncodeWithOptions(1, JSONEncodeOptions{})
+	require.EqualError(j.T(), err, "marshal failed")
+	require.EqualError(j.T(), JSON.DecodeStrictByJSONStr(`1`, new(int)), "unmarshal failed")
+}
+
+func (j *JSONCodecTest) TestGopherunJSON_SetCodec_case4() {
+	value := map[string]string{"html": "<a href=\"x?a=1&b=2\">", "text": `\u003c`}
+
+	// 无论引擎是否转义 HTML 字符，EncodeWithOptions 都按 EscapeHTML 输出
+	for _, codec := range []JSONCodec{StdJSONCodec{}, rawHTMLCodec{}, goJSONCodec{}} {
+		JSON.SetCodec(codec)
+
+		jsonStr, err := JSON.EncodeToJSONStrWithOptions(value, JSONEncodeOptions{})
+		require.Nil(j.T(), err)
+		require.Equal(j.T(), `{"html":"<a href=\"x?a=1&b=2\">","text":"\\u003c"}`, jsonStr, "%T", codec)
+
+		jsonStr, err = JSON.EncodeToJSONStrWithOptions(value, JSONEncodeOptions{EscapeHTML: true})
+		require.Nil(j.T(), err)
+		require.Equal(j.T(), `{"html":"\u003ca href=\"x?a=1\u0026b=2\"\u003e","text":"\\u003c"}`, jsonStr, "%T", codec)
+	}
+}
+
+// 基准测试：比较各引擎在典型数据上的编解码性能，接入新引擎时在 _benchmarkJSONCodecs 中追加一项即可，例如
+//
+//	{"jsoniter", jsoniter.ConfigCompatibleWithStandardLibrary},
+//
+// 运行：go test -run '^$' -bench BenchmarkJSONCodec -benchmem
+var _benchmarkJSONCodecs = []struct {
+	name  string
+	codec JSONCodec
+}{
+	{"std", StdJSONCodec{}},
+	{"go-json", goJSONCodec{}},
+}
+
+type benchmarkJSONUser struct {
+	ID       int64             `json:"id"`
+	Name     string            `json:"name"`
+	Email    string            `json:"email"`
+	Age      int               `json:"age"`
+	Active   bool              `json:"active"`
+	Score    float64           `json:"score"`
+	Tags     []string          `json:"tags"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func benchmarkJSONPayloads() []struct {
+	name string
+	obj  interface{}
+	new  func() interface{}
+} {
+	newUser := func(id int) benchmarkJSONUser {
+		return benchmarkJSONUser{
+			ID:       int64(id),
+			Name:     fmt.Sprintf("user-%d", id),
+			Email:    fmt.Sprintf("user-%d@example.com", id),
+			Age:      20 + id%50,
+			Active:   id%2 == 0,
+			Score:    float64(id) * 1.5,
+			Tags:     []string{"a", "b", "c"},
+			Metadata: map[string]string{"region": "cn", "tier": "gold"},
+		}
+	}
+	users := make([]benchmarkJSONUser, 1000)
+	for idx := range users {
+		users[idx] = newUser(idx)
+	}
+	generic := make(map[string]interface{}, 100)
+	for idx := 0; idx < 100; idx++ {
+		generic[fmt.Sprintf("key-%d", idx)] = map[string]interface{}{"n": idx, "s": "value", "l": []interface{}{1, "x", true}}
+	}
+
+	return []struct {
+		name string
+		obj  interface{}
+		new  func() interface{}
+	}{
+		{"small", newUser(1), func() interface{} { return &benchmarkJSONUser{} }},
+		{"large", users, func() interface{} { return &[]benchmarkJSONUser{} }},
+		{"generic", generic, func() interface{} { return &map[string]interface{}{} }},
+	}
+}
+
+func BenchmarkJSONCodec_Marshal(b *testing.B) {
+	for _, payload := range benchmarkJSONPayloads() {
+		for _, engine := range _benchmarkJSONCodecs {
+			b.Run(payload.name+"/"+engine.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for n := 0; n < b.N; n++ {
+					if _, err := engine.codec.Marshal(payload.obj); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkJSONCodec_Unmarshal(b *testing.B) {
+	for _, payload := range benchmarkJSONPayloads() {
+		data, err := StdJSONCodec{}.Marshal(payload.obj)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, engine := range _benchmarkJSONCodecs {
+			b.Run(payload.name+"/"+engine.name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(data)))
+				for n := 0; n < b.N; n++ {
+					if err := engine.codec.Unmarshal(data, payload.new()); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
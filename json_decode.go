@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JSONDecodeAs 将 data 解码为 T 类型的值，省去调用方声明变量并传入指针
+func JSONDecodeAs[T any](data []byte) (T, error) {
+	var obj T
+	if err := JSON.Decode(data, &obj); err != nil {
+		var zero T
+		return zero, err
+	}
+	return obj, nil
+}
+
+// JSONDecodeStrAs 将 JSON 字符串解码为 T 类型的值
+func JSONDecodeStrAs[T any](jsonStr string) (T, error) {
+	return JSONDecodeAs[T]([]byte(jsonStr))
+}
+
+// JSONMustDecodeAs 与 JSONDecodeAs 相同，解码失败时 panic，适用于测试与初始化代码
+func JSONMustDecodeAs[T any](data []byte) T {
+	obj, err := JSONDecodeAs[T](data)
+	if err != nil {
+		panic(fmt.Sprintf("gopherun: decode JSON as %T: %v", obj, err))
+	}
+	return obj
+}
+
+// JSONMustDecodeStrAs 与 JSONDecodeStrAs 相同，解码失败时 panic，适用于测试与初始化代码
+func JSONMustDecodeStrAs[T any](jsonStr string) T {
+	return JSONMustDecodeAs[T]([]byte(jsonStr))
+}
+
+// JSONDecodeInto 将 data 合并解码到 dst：JSON 中出现的字段覆盖 dst 中的值，未出现的字段保留原值，
+// 因此可以先在 dst 中填好默认值再解码。嵌套的结构体、结构体指针与 map 逐层合并，切片、数组与接口值整体替换。
+// 解码在 dst 的深拷贝上进行，失败时 dst 保持不变；dst 引用的指针、map、切片也不会被就地修改，
+// 同一份默认值可以安全地反复使用：
+//
+//	cfg := defaultConfig
+//	err := JSONDecodeInto(data, &cfg)
+func JSONDecodeInto[T any](data []byte, dst *T) error {
+	if dst == nil {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(dst)}
+	}
+
+	merged := new(T)
+	reflect.ValueOf(merged).Elem().Set(deepCopyValue(reflect.ValueOf(dst).Elem()))
+	if err := JSON.Decode(data, merged); err != nil {
+		return err
+	}
+	*dst = *merged
+	return nil
+}
+
+// deepCopyValue 深拷贝 v 中导出字段可达的指针、切片、数组、map 与接口，未导出字段按值浅拷贝；
+// 多次引用的同一个指针、map 或切片只拷贝一次，拷贝后保持相同的引用关系（包括循环引用）
+func deepCopyValue(v reflect.Value) reflect.Value {
+	return (&valueCopier{copied: map[valueCopyKey]reflect.Value{}}).copy(v)
+}
+
+// valueCopyKey 已拷贝的引用，同一地址可能是不同类型的值（如结构体与其第一个字段），长度用于区分切片
+type valueCopyKey struct {
+	ptr    uintptr
+	typ    reflect.Type
+	length int
+}
+
+type valueCopier struct {
+	copied map[valueCopyKey]reflect.Value
+}
+
+func (c *valueCopier) copy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		key := valueCopyKey{ptr: v.Pointer(), typ: v.Type()}
+		if copied, ok := c.copied[key]; ok {
+			return copied
+		}
+		copied := reflect.New(v.Type().Elem())
+		c.copied[key] = copied
+		copied.Elem().Set(c.copy(v.Elem()))
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(c.copy(v.Elem()))
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		key := valueCopyKey{ptr: v.Pointer(), typ: v.Type(), length: v.Len()}
+		if copied, ok := c.copied[key]; ok {
+			return copied
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		c.copied[key] = copied
+		for idx := 0; idx < v.Len(); idx++ {
+			copied.Index(idx).Set(c.copy(v.Index(idx)))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for idx := 0; idx < v.Len(); idx++ {
+			copied.Index(idx).Set(c.copy(v.Index(idx)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		key := valueCopyKey{ptr: v.Pointer(), typ: v.Type()}
+		if copied, ok := c.copied[key]; ok {
+			return copied
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		c.copied[key] = copied
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), c.copy(iter.Value()))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for idx := 0; idx < v.NumField(); idx++ {
+			if field := copied.Field(idx); field.CanSet() {
+				field.Set(c.copy(v.Field(idx)))
+			}
+		}
+		return copied
+	default:
+		return v
+	}
+}
@@ -0,0 +1,254 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// _jsonSnippetWidth 错误片段中错误位置两侧最多保留的字节数，避免压缩成一行的大文件输出过长
+const _jsonSnippetWidth = 60
+
+// JSONDecodeError 解码失败时的详细信息，包装 *json.SyntaxError 与 *json.UnmarshalTypeError
+type JSONDecodeError struct {
+	Line     int    // 行号，从 1 开始
+	Column   int    // 列号（字节），从 1 开始
+	Offset   int64  // 相对于文本开头的字节偏移量
+	Path     string // 出错的值的 JSON Pointer，如 /servers/2/port，根节点为 ""
+	Expected string // 期望的 Go 类型，仅类型错误时有值，如 int
+	Actual   string // 实际的 JSON 类型，仅类型错误时有值，如 string
+	Snippet  string // 出错所在行及指向错误位置的 ^ 标记，可直接输出给用户
+	Err      error  // 原始错误
+}
+
+func (e *JSONDecodeError) Error() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "line %d, column %d", e.Line, e.Column)
+	if e.Path != "" {
+		fmt.Fprintf(&builder, ", path %q", e.Path)
+	}
+	if e.Expected != "" {
+		fmt.Fprintf(&builder, ": expected %s, got %s", e.Expected, e.Actual)
+	}
+	fmt.Fprintf(&builder, ": %v", e.Err)
+	return builder.String()
+}
+
+func (e *JSONDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// newJSONDecodeError 为 data 的解码错误补充位置信息，其他错误（包括自定义 JSONCodec 返回的错误）原样返回
+func newJSONDecodeError(data []byte, err error) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		decodeErr = &JSONDecodeError{Err: err}
+		offset    int
+	)
+	switch {
+	case errors.As(err, new(*JSONDecodeError)):
+		return err
+	case errors.As(err, &syntaxErr):
+		// SyntaxError.Offset 指向出错字符之后；输入不完整时指向文本末尾
+		offset = int(syntaxErr.Offset) - 1
+		if offset < 0 || syntaxErr.Error() == "unexpected end of JSON input" {
+			offset = len(data)
+		}
+		_, decodeErr.Path = locateJSONValue(data, offset+1)
+	case errors.As(err, &typeErr):
+		// UnmarshalTypeError.Offset 指向值之后（对象与数组为起始括号之后），定位到该值的起始位置
+		offset, decodeErr.Path = locateJSONValue(data, int(typeErr.Offset))
+		if typeErr.Type != nil {
+			decodeErr.Expected = typeErr.Type.String()
+		}
+		decodeErr.Actual = jsonLiteralKind(data, offset)
+	default:
+		return err
+	}
+	if offset > len(data) {
+		offset = len(data)
+	}
+
+	decodeErr.Offset = int64(offset)
+	decodeErr.Line, decodeErr.Column, decodeErr.Snippet = jsonErrorSnippet(data, offset)
+	return decodeErr
+}
+
+// locateJSONValue 返回 end 之前最后一个开始的值的偏移量及其 JSON Pointer，对不合法的文本尽量给出结果
+func locateJSONValue(data []byte, end int) (int, string) {
+	type frame struct {
+		array     bool
+		index     int
+		key       string
+		expectKey bool
+	}
+	var (
+		stack      []frame
+		valueStart int
+		path       string
+	)
+	pointer := func() string {
+		var builder strings.Builder
+		for _, f := range stack {
+			switch {
+			case f.array:
+				builder.WriteString("/" + strconv.Itoa(f.index))
+			case !f.expectKey:
+				builder.WriteString("/" + escapeJSONPointerToken(f.key))
+			}
+		}
+		return builder.String()
+	}
+	markValue := func(pos int) {
+		valueStart, path = pos, pointer()
+	}
+
+	for pos := 0; pos < end && pos < len(data); {
+		switch ch := data[pos]; ch {
+		case ' ', '\t', '\n', '\r', ':':
+			pos++
+		case '{', '[':
+			markValue(pos)
+			stack = append(stack, frame{array: ch == '[', expectKey: ch == '{'})
+			pos++
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			pos++
+		case ',':
+			if top := len(stack) - 1; top >= 0 {
+				if stack[top].array {
+					stack[top].index++
+				} else {
+					stack[top].expectKey = true
+				}
+			}
+			pos++
+		case '"':
+			next := pos + 1
+			for next < len(data) && data[next] != '"' {
+				if data[next] == '\\' {
+					next++
+				}
+				next++
+			}
+			if next++; next > len(data) {
+				next = len(data)
+			}
+			if top := len(stack) - 1; top >= 0 && stack[top].expectKey {
+				var key string
+				if err := json.Unmarshal(data[pos:next], &key); err != nil {
+					key = strings.Trim(string(data[pos:next]), `"`)
+				}
+				stack[top].key, stack[top].expectKey = key, false
+			} else {
+				markValue(pos)
+			}
+			pos = next
+		default:
+			markValue(pos)
+			for pos < len(data) && bytes.IndexByte([]byte(" \t\n\r,:{}[]\""), data[pos]) < 0 {
+				pos++
+			}
+			if pos == valueStart {
+				pos++
+			}
+		}
+	}
+	return valueStart, path
+}
+
+// jsonLiteralKind 按 offset 处的首字符判断值的 JSON 类型
+func jsonLiteralKind(data []byte, offset int) string {
+	if offset >= len(data) {
+		return "end of input"
+	}
+	switch ch := data[offset]; {
+	case ch == '{':
+		return "object"
+	case ch == '[':
+		return "array"
+	case ch == '"':
+		return "string"
+	case ch == 't' || ch == 'f':
+		return "boolean"
+	case ch == 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
+
+// jsonErrorSnippet 返回 offset 处的行列号，以及出错所在行与指向出错位置的 ^ 标记，例如：
+//
+//	4 |   "port": "8080",
+//	  |           ^
+func jsonErrorSnippet(data []byte, offset int) (line, column int, snippet string) {
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	line = bytes.Count(data[:offset], []byte{'\n'}) + 1
+	column = offset - lineStart + 1
+
+	lineEnd := bytes.IndexByte(data[offset:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(data)
+	} else {
+		lineEnd += offset
+	}
+	text := data[lineStart:lineEnd]
+	text = bytes.TrimSuffix(text, []byte{'\r'})
+
+	// 过长的行只保留错误位置附近的内容
+	caret := offset - lineStart
+	prefix, suffix := "", ""
+	if caret > _jsonSnippetWidth {
+		cut := caret - _jsonSnippetWidth
+		for cut < len(text) && !utf8.RuneStart(text[cut]) {
+			cut++
+		}
+		text, caret, prefix = text[cut:], caret-cut, "..."
+	}
+	if limit := caret + _jsonSnippetWidth; limit < len(text) {
+		for limit > caret && !utf8.RuneStart(text[limit]) {
+			limit--
+		}
+		text, suffix = text[:limit], "..."
+	}
+	if caret > len(text) {
+		caret = len(text)
+	}
+
+	// ^ 之前的制表符原样保留，其余字符（含多字节字符）替换为一个空格，保证对齐
+	var marker strings.Builder
+	marker.WriteString(strings.Repeat(" ", len(prefix)))
+	for _, r := range string(text[:caret]) {
+		if r == '\t' {
+			marker.WriteByte('\t')
+		} else {
+			marker.WriteByte(' ')
+		}
+	}
+	marker.WriteByte('^')
+
+	number := strconv.Itoa(line)
+	gutter := strings.Repeat(" ", len(number))
+	snippet = fmt.Sprintf("%s | %s%s%s\n%s | %s", number, prefix, text, suffix, gutter, marker.String())
+	return line, column, snippet
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type JSONDecodeErrorTest struct {
+	suite.Suite
+}
+
+func TestJSONDecodeErrorTest(t *testing.T) {
+	suite.Run(t, new(JSONDecodeErrorTest))
+}
+
+type decodeErrorTestConfig struct {
+	Name    string `json:"name"`
+	Servers []struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	} `json:"servers"`
+	Labels map[string]bool `json:"labels"`
+}
+
+func (j *JSONDecodeErrorTest) TestGopherunJSON_Decode_typeError() {
+	data := "{\n  \"name\": \"app\",\n  \"servers\": [\n    {\"host\": \"a\", \"port\": 80},\n    {\"host\": \"b\", \"port\": \"8080\"}\n  ]\n}"
+
+	var (
+		cfg       decodeErrorTestConfig
+		decodeErr *JSONDecodeError
+		typeErr   *json.UnmarshalTypeError
+	)
+	err := JSON.DecodeByJSONStr(data, &cfg)
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.True(j.T(), errors.As(err, &typeErr))
+	require.Equal(j.T(), 5, decodeErr.Line)
+	require.Equal(j.T(), 27, decodeErr.Column)
+	require.Equal(j.T(), int64(strings.Index(data, `"8080"`)), decodeErr.Offset)
+	require.Equal(j.T(), "/servers/1/port", decodeErr.Path)
+	require.Equal(j.T(), "int", decodeErr.Expected)
+	require.Equal(j.T(), "string", decodeErr.Actual)
+	require.Equal(j.T(), "5 |     {\"host\": \"b\", \"port\": \"8080\"}\n  |                           ^", decodeErr.Snippet)
+	require.True(j.T(), strings.HasPrefix(err.Error(), `line 5, column 27, path "/servers/1/port": expected int, got string: json: cannot unmarshal`))
+
+	// 对象与数组类型不匹配时定位到起始括号
+	err = JSON.DecodeByJSONStr(`{"labels": {"a~b/c": [true]}}`, &cfg)
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.Equal(j.T(), "/labels/a~0b~1c", decodeErr.Path)
+	require.Equal(j.T(), 22, decodeErr.Column)
+	require.Equal(j.T(), "bool", decodeErr.Expected)
+	require.Equal(j.T(), "array", decodeErr.Actual)
+
+	err = JSON.DecodeByJSONStr(`[1]`, &cfg)
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.Equal(j.T(), "", decodeErr.Path)
+	require.Equal(j.T(), 1, decodeErr.Column)
+	require.Equal(j.T(), "array", decodeErr.Actual)
+}
+
+func (j *JSONDecodeErrorTest) TestGopherunJSON_Decode_syntaxError() {
+	var (
+		cfg       decodeErrorTestConfig
+		decodeErr *JSONDecodeError
+		syntaxErr *json.SyntaxError
+	)
+	data := "{\n\t\"name\": \"app\",\n\t\"servers\": [{\"host\": \"a\",}]\n}"
+	err := JSON.DecodeByJSONStr(data, &cfg)
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.True(j.T(), errors.As(err, &syntaxErr))
+	require.Equal(j.T(), 3, decodeErr.Line)
+	require.Equal(j.T(), 27, decodeErr.Column)
+	require.Equal(j.T(), "/servers/0/host", decodeErr.Path)
+	require.Equal(j.T(), "", decodeErr.Expected)
+	require.Equal(j.T(), "3 | \t\"servers\": [{\"host\": \"a\",}]\n  | \t                         ^", decodeErr.Snippet)
+
+	// 输入不完整时指向末尾
+	err = JSON.DecodeByJSONStr("{\n  \"name\": \"app\",", &cfg)
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.Equal(j.T(), 2, decodeErr.Line)
+	require.Equal(j.T(), 17, decodeErr.Column)
+	require.Equal(j.T(), "2 |   \"name\": \"app\",\n  |                 ^", decodeErr.Snippet)
+
+	// 过长的行只保留错误位置附近的内容
+	data = `{"name": "` + strings.Repeat("x", 100) + `", "servers": 1x, "labels": {"` + strings.Repeat("y", 100) + `": true}}`
+	err = JSON.DecodeByJSONStr(data, &cfg)
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.Equal(j.T(), strings.Index(data, "1x")+2, decodeErr.Column)
+	require.Equal(j.T(), "/servers", decodeErr.Path)
+	lines := strings.Split(decodeErr.Snippet, "\n")
+	require.Len(j.T(), lines, 2)
+	require.True(j.T(), strings.HasPrefix(lines[0], "1 | ..."))
+	require.True(j.T(), strings.HasSuffix(lines[0], "..."))
+	require.Equal(j.T(), strings.Index(lines[0], "1x")+1, strings.Index(lines[1], "^"))
+
+	// 多字节字符不影响 ^ 的对齐
+	err = JSON.DecodeByJSONStr(`{"name": "名称" x}`, &cfg)
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.Equal(j.T(), "1 | {\"name\": \"名称\" x}\n  |               ^", decodeErr.Snippet)
+}
+
+func (j *JSONDecodeErrorTest) TestGopherunJSON_Decode_otherError() {
+	var cfg decodeErrorTestConfig
+	err := JSON.Decode([]byte(`{}`), cfg)
+	var invalidErr *json.InvalidUnmarshalError
+	require.True(j.T(), errors.As(err, &invalidErr))
+	require.False(j.T(), errors.As(err, new(*JSONDecodeError)))
+
+	// DecodeWithOptions 同样给出位置信息
+	var decodeErr *JSONDecodeError
+	err = JSON.DecodeWithOptions([]byte("[1,\n2,]"), &[]int{}, JSONDecodeOptions{UseNumber: true})
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.Equal(j.T(), 2, decodeErr.Line)
+	require.Equal(j.T(), 3, decodeErr.Column)
+	require.Equal(j.T(), "/1", decodeErr.Path)
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"reflect"
+	"testing"
+)
+
+type JSONDecodeTest struct {
+	suite.Suite
+}
+
+func TestJSONDecodeTest(t *testing.T) {
+	suite.Run(t, new(JSONDecodeTest))
+}
+
+type decodeTestServer struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type decodeTestConfig struct {
+	Name    string            `json:"name"`
+	Debug   bool              `json:"debug"`
+	Server  *decodeTestServer `json:"server"`
+	Labels  map[string]string `json:"labels"`
+	Plugins []string          `json:"plugins"`
+	Extra   interface{}       `json:"extra"`
+	version int
+}
+
+func (j *JSONDecodeTest) TestJSONDecodeAs() {
+	user, err := JSONDecodeAs[decodeTestServer]([]byte(`{"host":"localhost","port":8080}`))
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), decodeTestServer{Host: "localhost", Port: 8080}, user)
+
+	ptr, err := JSONDecodeStrAs[*decodeTestServer](`{"host":"localhost"}`)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), &decodeTestServer{Host: "localhost"}, ptr)
+
+	list, err := JSONDecodeStrAs[[]int](`[1,2,3]`)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []int{1, 2, 3}, list)
+
+	// 解码失败时返回零值
+	user, err = JSONDecodeStrAs[decodeTestServer](`{"host":"localhost","port":"8080"}`)
+	require.NotNil(j.T(), err)
+	require.Equal(j.T(), decodeTestServer{}, user)
+
+	_, err = JSONDecodeAs[map[string]int]([]byte(`{`))
+	require.NotNil(j.T(), err)
+}
+
+func (j *JSONDecodeTest) TestJSONMustDecodeAs() {
+	require.Equal(j.T(), map[string]int{"a": 1}, JSONMustDecodeAs[map[string]int]([]byte(`{"a":1}`)))
+	require.Equal(j.T(), "abc", JSONMustDecodeStrAs[string](`"abc"`))
+
+	require.Panics(j.T(), func() {
+		JSONMustDecodeAs[int]([]byte(`"abc"`))
+	})
+	require.Panics(j.T(), func() {
+		JSONMustDecodeStrAs[int](`{`)
+	})
+}
+
+func (j *JSONDecodeTest) TestJSONDecodeInto() {
+	defaults := decodeTestConfig{
+		Name:    "app",
+		Server:  &decodeTestServer{Host: "localhost", Port: 8080},
+		Labels:  map[string]string{"env": "dev"},
+		Plugins: []string{"a", "b"},
+		Extra:   map[string]interface{}{"k": "v"},
+		version: 3,
+	}
+
+	cfg := defaults
+	err := JSONDecodeInto([]byte(`{"debug":true,"server":{"port":9090},"labels":{"team":"x"},"plugins":["c"],"extra":{"n":1}}`), &cfg)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), decodeTestConfig{
+		Name:    "app",
+		Debug:   true,
+		Server:  &decodeTestServer{Host: "localhost", Port: 9090},
+		Labels:  map[string]string{"env": "dev", "team": "x"},
+		Plugins: []string{"c"},
+		Extra:   map[string]interface{}{"n": float64(1)},
+		version: 3,
+	}, cfg)
+
+	// 默认值引用的指针、map、切片没有被修改
+	require.Equal(j.T(), &decodeTestServer{Host: "localhost", Port: 8080}, defaults.Server)
+	require.Equal(j.T(), map[string]string{"env": "dev"}, defaults.Labels)
+	require.Equal(j.T(), []string{"a", "b"}, defaults.Plugins)
+	require.Equal(j.T(), map[string]interface{}{"k": "v"}, defaults.Extra)
+
+	// 解码失败时 dst 保持不变
+	cfg = defaults
+	err = JSONDecodeInto([]byte(`{"name":"other","server":{"port":"x"}}`), &cfg)
+	require.NotNil(j.T(), err)
+	require.Equal(j.T(), defaults, cfg)
+
+	var nilCfg *decodeTestConfig
+	require.NotNil(j.T(), JSONDecodeInto([]byte(`{}`), nilCfg))
+
+	var anything interface{}
+	require.Nil(j.T(), JSONDecodeInto([]byte(`[1]`), &anything))
+	require.Equal(j.T(), []interface{}{float64(1)}, anything)
+
+	numbers := [3]int{1, 2, 3}
+	require.Nil(j.T(), JSONDecodeInto([]byte(`[4]`), &numbers))
+	require.Equal(j.T(), [3]int{4, 0, 0}, numbers)
+}
+
+type decodeTestNode struct {
+	Name   string          `json:"name"`
+	Parent *decodeTestNode `json:"-"`
+	Next   *decodeTestNode `json:"next,omitempty"`
+}
+
+func (j *JSONDecodeTest) TestJSONDecodeInto_Cycle() {
+	// 默认值中存在循环引用时，拷贝保持相同的引用关系
+	root := &decodeTestNode{Name: "root"}
+	root.Next = &decodeTestNode{Name: "child", Parent: root}
+	root.Parent = root
+
+	node := *root
+	require.Nil(j.T(), JSONDecodeInto([]byte(`{"next":{"name":"renamed"}}`), &node))
+	require.Equal(j.T(), "renamed", node.Next.Name)
+	require.Same(j.T(), node.Next.Parent, node.Parent)
+	require.Same(j.T(), node.Parent, node.Parent.Parent)
+	require.NotSame(j.T(), root, node.Parent)
+
+	// 原始值没有被修改
+	require.Equal(j.T(), "child", root.Next.Name)
+
+	// 自身包含自身的 map
+	original := map[string]interface{}{"k": "v"}
+	original["self"] = original
+	extra := original
+	require.Nil(j.T(), JSONDecodeInto([]byte(`{"n":1}`), &extra))
+	require.Equal(j.T(), float64(1), extra["n"])
+	require.Equal(j.T(), reflect.ValueOf(extra).Pointer(), reflect.ValueOf(extra["self"]).Pointer())
+	_, ok := original["n"]
+	require.False(j.T(), ok)
+}
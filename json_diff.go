@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONDiffKind 差异类别
+type JSONDiffKind string
+
+// 差异类别
+const (
+	JSONDiffAdded   JSONDiffKind = "added"   // 仅存在于新文档
+	JSONDiffRemoved JSONDiffKind = "removed" // 仅存在于旧文档
+	JSONDiffChanged JSONDiffKind = "changed" // 两边都存在但值不同
+)
+
+// JSONDifference 一处路径级别的差异
+type JSONDifference struct {
+	Path string       // 差异位置，JSON Pointer 格式，文档根为 ""
+	Kind JSONDiffKind // 差异类别
+	Old  interface{}  // 旧值，Kind 为 added 时为 nil
+	New  interface{}  // 新值，Kind 为 removed 时为 nil
+}
+
+func (d JSONDifference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "/"
+	}
+	switch d.Kind {
+	case JSONDiffAdded:
+		return fmt.Sprintf("+ %s: %s", path, formatJSONDiffValue(d.New))
+	case JSONDiffRemoved:
+		return fmt.Sprintf("- %s: %s", path, formatJSONDiffValue(d.Old))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", path, formatJSONDiffValue(d.Old), formatJSONDiffValue(d.New))
+	}
+}
+
+// JSONDiff 两个文档之间的全部差异
+type JSONDiff []JSONDifference
+
+// String 每行一处差异，便于在测试失败信息和审计日志中输出
+func (d JSONDiff) String() string {
+	lines := make([]string, 0, len(d))
+	for _, difference := range d {
+		lines = append(lines, difference.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Equal 语义化比较两个 JSON：对象忽略键顺序，数字按数值比较（1 与 1.0 相等）。
+// a、b 可以是原始 JSON（[]byte 或 json.RawMessage），也可以是任意可编码的 Go 值（如结构体、map）。
+func (i GopherunJSON) Equal(a, b interface{}) (bool, error) {
+	aTree, err := normalizeJSONValue(a)
+	if err != nil {
+		return false, err
+	}
+	bTree, err := normalizeJSONValue(b)
+	if err != nil {
+		return false, err
+	}
+	return jsonValuesEqual(aTree, bTree), nil
+}
+
+// Diff 列出从 a 到 b 的路径级别差异，比较规则与 Equal 相同；数组按下标逐个比较。
+// 差异按路径深度优先的顺序返回，对象键按字典序排列；没有差异时返回空切片。
+func (i GopherunJSON) Diff(a, b interface{}) (JSONDiff, error) {
+	aTree, err := normalizeJSONValue(a)
+	if err != nil {
+		return nil, err
+	}
+	bTree, err := normalizeJSONValue(b)
+	if err != nil {
+		return nil, err
+	}
+	return diffJSONValues(JSONDiff{}, "", aTree, bTree), nil
+}
+
+// normalizeJSONValue 将原始 JSON 或 Go 值统一转换为数字使用 json.Number 的树
+func normalizeJSONValue(value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case []byte, json.RawMessage:
+		return decodeJSONDoc(value)
+	}
+
+	data, err := JSON.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONDoc(data)
+}
+
+func diffJSONValues(diff JSONDiff, path string, a, b interface{}) JSONDiff {
+	if jsonValuesEqual(a, b) {
+		return diff
+	}
+
+	switch aValue := a.(type) {
+	case map[string]interface{}:
+		bValue, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		// a 与 b 的键合并后按字典序处理
+		union := make(map[string]interface{}, len(aValue)+len(bValue))
+		for key := range aValue {
+			union[key] = nil
+		}
+		for key := range bValue {
+			union[key] = nil
+		}
+		for _, key := range sortedJSONKeys(union) {
+			childPath := path + "/" + escapeJSONPointerToken(key)
+			aChild, aOK := aValue[key]
+			bChild, bOK := bValue[key]
+			switch {
+			case !bOK:
+				diff = append(diff, JSONDifference{Path: childPath, Kind: JSONDiffRemoved, Old: aChild})
+			case !aOK:
+				diff = append(diff, JSONDifference{Path: childPath, Kind: JSONDiffAdded, New: bChild})
+			default:
+				diff = diffJSONValues(diff, childPath, aChild, bChild)
+			}
+		}
+		return diff
+	case []interface{}:
+		bValue, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		for idx := 0; idx < len(aValue) || idx < len(bValue); idx++ {
+			childPath := path + "/" + strconv.Itoa(idx)
+			switch {
+			case idx >= len(bValue):
+				diff = append(diff, JSONDifference{Path: childPath, Kind: JSONDiffRemoved, Old: aValue[idx]})
+			case idx >= len(aValue):
+				diff = append(diff, JSONDifference{Path: childPath, Kind: JSONDiffAdded, New: bValue[idx]})
+			default:
+				diff = diffJSONValues(diff, childPath, aValue[idx], bValue[idx])
+			}
+		}
+		return diff
+	}
+
+	return append(diff, JSONDifference{Path: path, Kind: JSONDiffChanged, Old: a, New: b})
+}
+
+func formatJSONDiffValue(value interface{}) string {
+	data, err := JSON.EncodeWithOptions(value, JSONEncodeOptions{})
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type JSONDiffTest struct {
+	suite.Suite
+}
+
+func TestJSONDiffTest(t *testing.T) {
+	suite.Run(t, new(JSONDiffTest))
+}
+
+func (j *JSONDiffTest) TestGopherunJSON_Equal() {
+	equal, err := JSON.Equal([]byte(`{"a":1,"b":[1,2,{"c":null}]}`), []byte(`{"b":[1.0,2e0,{"c":null}],"a":1.00}`))
+	require.Nil(j.T(), err)
+	require.True(j.T(), equal)
+
+	equal, err = JSON.Equal([]byte(`[1,2]`), []byte(`[2,1]`))
+	require.Nil(j.T(), err)
+	require.False(j.T(), equal)
+
+	// 大整数按精确数值比较
+	equal, err = JSON.Equal([]byte(`9007199254740993`), []byte(`9007199254740992`))
+	require.Nil(j.T(), err)
+	require.False(j.T(), equal)
+
+	equal, err = JSON.Equal([]byte(`{"a":"1"}`), []byte(`{"a":1}`))
+	require.Nil(j.T(), err)
+	require.False(j.T(), equal)
+
+	// 结构体与原始 JSON 比较
+	type User struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	equal, err = JSON.Equal(User{Name: "zhangsan", Age: 12}, json.RawMessage(`{"age":12.0,"name":"zhangsan"}`))
+	require.Nil(j.T(), err)
+	require.True(j.T(), equal)
+
+	equal, err = JSON.Equal(map[string]interface{}{"a": 1}, map[string]int{"a": 1})
+	require.Nil(j.T(), err)
+	require.True(j.T(), equal)
+
+	_, err = JSON.Equal([]byte(`{`), []byte(`{}`))
+	require.NotNil(j.T(), err)
+	_, err = JSON.Equal([]byte(`{}`), make(chan int))
+	require.NotNil(j.T(), err)
+}
+
+func (j *JSONDiffTest) TestGopherunJSON_Diff() {
+	a := []byte(`{"name":"zhangsan","age":12,"tags":["a","b","c"],"address":{"city":"beijing"},"x/y":1}`)
+	b := []byte(`{"name":"lisi","age":12.0,"tags":["a","x"],"address":[],"email":"a@b.c","x/y":1}`)
+
+	diff, err := JSON.Diff(a, b)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), JSONDiff{
+		{Path: "/address", Kind: JSONDiffChanged, Old: map[string]interface{}{"city": "beijing"}, New: []interface{}{}},
+		{Path: "/email", Kind: JSONDiffAdded, New: "a@b.c"},
+		{Path: "/name", Kind: JSONDiffChanged, Old: "zhangsan", New: "lisi"},
+		{Path: "/tags/1", Kind: JSONDiffChanged, Old: "b", New: "x"},
+		{Path: "/tags/2", Kind: JSONDiffRemoved, Old: "c"},
+	}, diff)
+
+	require.Equal(j.T(), `~ /address: {"city":"beijing"} -> []
++ /email: "a@b.c"
+~ /name: "zhangsan" -> "lisi"
+~ /tags/1: "b" -> "x"
+- /tags/2: "c"`, diff.String())
+
+	// 只存在于 a 或 b 中的键同样按字典序排列
+	diff, err = JSON.Diff([]byte(`{"b":1,"d":1}`), []byte(`{"a":1,"c":1}`))
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "+ /a: 1\n- /b: 1\n+ /c: 1\n- /d: 1", diff.String())
+
+	diff, err = JSON.Diff([]byte(`1`), []byte(`2`))
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "~ /: 1 -> 2", diff.String())
+
+	diff, err = JSON.Diff([]byte(`[1]`), []byte(`[1,{"a":1}]`))
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `+ /1: {"a":1}`, diff.String())
+
+	diff, err = JSON.Diff([]byte(`{"a":1}`), []byte(`{"a":1.0}`))
+	require.Nil(j.T(), err)
+	require.Empty(j.T(), diff)
+
+	_, err = JSON.Diff([]byte(`{`), []byte(`{}`))
+	require.NotNil(j.T(), err)
+	_, err = JSON.Diff([]byte(`{}`), []byte(`{`))
+	require.NotNil(j.T(), err)
+}
@@ -0,0 +1,391 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"unicode/utf8"
+)
+
+// JSONEncodeOptions 编码配置，零值表示紧凑输出且不转义 HTML 字符
+type JSONEncodeOptions struct {
+	// Prefix 缩进输出时每行的前缀（第一行除外）
+	Prefix string
+
+	// Indent 缩进字符串，Prefix 与 Indent 均为空时输出紧凑格式
+	Indent string
+
+	// EscapeHTML 为 true 时将 <、>、& 转义为 \u003c、\u003e、\u0026（与 Encode 的行为一致）
+	EscapeHTML bool
+
+	// SortKeys 为 true 时所有对象（包括结构体）的字段均按键名排序输出
+	SortKeys bool
+
+	// OmitEmpty 为 true 时全局忽略值为 null、""、[]、{} 的对象字段，数组中的元素不受影响
+	OmitEmpty bool
+
+	// Int64AsString 为 true 时将 int、int64、uint、uint64 类型的值编码为字符串（如 "9007199254740993"），
+	// 避免 JavaScript 客户端丢失精度；实现了 json.Marshaler 或 encoding.TextMarshaler 的类型不受影响
+	Int64AsString bool
+}
+
+// EncodeWithOptions 按 opts 将 obj 编码为 JSON，编码使用 SetCodec 设置的引擎
+func (i GopherunJSON) EncodeWithOptions(obj interface{}, opts JSONEncodeOptions) ([]byte, error) {
+	data, err := i.Codec().Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	unions := !_jsonTypes.empty() && hasJSONDiscriminators(reflect.ValueOf(obj))
+	if !opts.SortKeys && !opts.OmitEmpty && !opts.Int64AsString && !unions {
+		// 引擎是否转义 HTML 字符不确定，统一按 opts 处理
+		data = escapeJSONHTML(data, opts.EscapeHTML)
+	} else {
+		tree, err := decodeOrderedJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		if unions {
+			addJSONDiscriminators(tree, reflect.ValueOf(obj), map[reflect.Type]map[string][]int{})
+		}
+		if opts.Int64AsString {
+			tree = quoteJSONInt64Values(tree, reflect.ValueOf(obj), map[reflect.Type]map[string][]int{})
+		}
+		if opts.SortKeys {
+			tree = sortJSONKeys(tree)
+		}
+		if opts.OmitEmpty {
+			tree = omitEmptyJSONFields(tree)
+		}
+
+		var out bytes.Buffer
+		writeOrderedJSON(&out, tree, opts.EscapeHTML)
+		data = out.Bytes()
+	}
+
+	if opts.Prefix == "" && opts.Indent == "" {
+		return data, nil
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, opts.Prefix, opts.Indent); err != nil {
+		return nil, err
+	}
+	return indented.Bytes(), nil
+}
+
+// EncodeToJSONStrWithOptions 按 opts 将 obj 编码为 JSON 字符串
+func (i GopherunJSON) EncodeToJSONStrWithOptions(obj interface{}, opts JSONEncodeOptions) (jsonStr string, err error) {
+	bytes, err := i.EncodeWithOptions(obj, opts)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// Pretty 返回便于阅读的 JSON 字符串（两空格缩进、不转义 HTML），用于日志输出；
+// 编码失败时退回到 fmt 的 %+v 格式，因此总能得到结果。
+func (i GopherunJSON) Pretty(obj interface{}) string {
+	jsonStr, err := i.EncodeToJSONStrWithOptions(obj, JSONEncodeOptions{Indent: "  "})
+	if err != nil {
+		return fmt.Sprintf("%+v", obj)
+	}
+	return jsonStr
+}
+
+// escapeJSONHTML escape 为 true 时将 <、>、& 转义为 \u003c、\u003e、\u0026，否则还原这三个转义序列。
+// 合法的 JSON 中这些字符只会出现在字符串内，因此可以直接替换；还原时跳过 \\ 等其他转义序列。
+func escapeJSONHTML(data []byte, escape bool) []byte {
+	if escape {
+		if bytes.IndexAny(data, "<>&") < 0 {
+			return data
+		}
+		var buf bytes.Buffer
+		for _, b := range data {
+			switch b {
+			case '<', '>', '&':
+				fmt.Fprintf(&buf, "\\u%04x", b)
+			default:
+				buf.WriteByte(b)
+			}
+		}
+		return buf.Bytes()
+	}
+
+	if !bytes.Contains(data, []byte(`\u00`)) {
+		return data
+	}
+	var buf bytes.Buffer
+	for idx := 0; idx < len(data); idx++ {
+		if data[idx] != '\\' || idx+1 >= len(data) {
+			buf.WriteByte(data[idx])
+			continue
+		}
+		if data[idx+1] == 'u' && idx+6 <= len(data) {
+			switch string(data[idx+2 : idx+6]) {
+			case "003c", "003C":
+				buf.WriteByte('<')
+				idx += 5
+				continue
+			case "003e", "003E":
+				buf.WriteByte('>')
+				idx += 5
+				continue
+			case "0026":
+				buf.WriteByte('&')
+				idx += 5
+				continue
+			}
+		}
+		// 其他转义序列原样保留，跳过被转义的字符
+		buf.Write(data[idx : idx+2])
+		idx++
+	}
+	return buf.Bytes()
+}
+
+// orderedJSONObject 保留字段顺序的 JSON 对象
+type orderedJSONObject struct {
+	keys   []string
+	values []interface{}
+}
+
+// set 设置 key 对应的值，key 已存在时原位替换，否则追加到末尾
+func (o *orderedJSONObject) set(key string, value interface{}) {
+	for idx, existing := range o.keys {
+		if existing == key {
+			o.values[idx] = value
+			return
+		}
+	}
+	o.keys = append(o.keys, key)
+	o.values = append(o.values, value)
+}
+
+// get 返回 key 对应的值
+func (o *orderedJSONObject) get(key string) (interface{}, bool) {
+	for idx, existing := range o.keys {
+		if existing == key {
+			return o.values[idx], true
+		}
+	}
+	return nil, false
+}
+
+// decodeOrderedJSON 将 JSON 解码为保留字段顺序的树，数字使用 json.Number 保存原始文本。
+// 节点类型为 *orderedJSONObject、[]interface{}、string、json.Number、bool 或 nil。
+func decodeOrderedJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	value, err := decodeOrderedJSONValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid character after top-level value at offset %d", decoder.InputOffset())
+	}
+	return value, nil
+}
+
+func decodeOrderedJSONValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := &orderedJSONObject{}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrderedJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object.keys = append(object.keys, keyToken.(string))
+			object.values = append(object.values, value)
+		}
+		_, err = decoder.Token()
+		return object, err
+	case json.Delim('['):
+		array := []interface{}{}
+		for decoder.More() {
+			value, err := decodeOrderedJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token()
+		return array, err
+	default:
+		return token, nil
+	}
+}
+
+// sortJSONKeys 递归地按键名排序对象字段
+func sortJSONKeys(node interface{}) interface{} {
+	switch value := node.(type) {
+	case *orderedJSONObject:
+		indexes := make([]int, len(value.keys))
+		for idx := range indexes {
+			indexes[idx] = idx
+		}
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return value.keys[indexes[a]] < value.keys[indexes[b]]
+		})
+
+		sorted := &orderedJSONObject{}
+		for _, idx := range indexes {
+			sorted.keys = append(sorted.keys, value.keys[idx])
+			sorted.values = append(sorted.values, sortJSONKeys(value.values[idx]))
+		}
+		return sorted
+	case []interface{}:
+		for idx := range value {
+			value[idx] = sortJSONKeys(value[idx])
+		}
+		return value
+	default:
+		return node
+	}
+}
+
+// omitEmptyJSONFields 递归地删除值为 null、""、[]、{} 的对象字段（先处理子节点，因此清理后变空的对象也会被删除）
+func omitEmptyJSONFields(node interface{}) interface{} {
+	switch value := node.(type) {
+	case *orderedJSONObject:
+		kept := &orderedJSONObject{}
+		for idx, key := range value.keys {
+			child := omitEmptyJSONFields(value.values[idx])
+			if isEmptyJSONValue(child) {
+				continue
+			}
+			kept.keys = append(kept.keys, key)
+			kept.values = append(kept.values, child)
+		}
+		return kept
+	case []interface{}:
+		for idx := range value {
+			value[idx] = omitEmptyJSONFields(value[idx])
+		}
+		return value
+	default:
+		return node
+	}
+}
+
+func isEmptyJSONValue(node interface{}) bool {
+	switch value := node.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case []interface{}:
+		return len(value) == 0
+	case *orderedJSONObject:
+		return len(value.keys) == 0
+	default:
+		return false
+	}
+}
+
+// writeOrderedJSON 以紧凑格式输出有序树
+func writeOrderedJSON(buf *bytes.Buffer, node interface{}, escapeHTML bool) {
+	switch value := node.(type) {
+	case *orderedJSONObject:
+		buf.WriteByte('{')
+		for idx, key := range value.keys {
+			if idx > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, key, escapeHTML)
+			buf.WriteByte(':')
+			writeOrderedJSON(buf, value.values[idx], escapeHTML)
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for idx, item := range value {
+			if idx > 0 {
+				buf.WriteByte(',')
+			}
+			writeOrderedJSON(buf, item, escapeHTML)
+		}
+		buf.WriteByte(']')
+	case string:
+		writeJSONString(buf, value, escapeHTML)
+	case json.Number:
+		buf.WriteString(value.String())
+	case bool:
+		if value {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	default:
+		buf.WriteString("null")
+	}
+}
+
+const _hexDigits = "0123456789abcdef"
+
+// writeJSONString 按 encoding/json 的规则输出字符串字面量
+func writeJSONString(buf *bytes.Buffer, s string, escapeHTML bool) {
+	buf.WriteByte('"')
+	for idx := 0; idx < len(s); {
+		if b := s[idx]; b < utf8.RuneSelf {
+			switch {
+			case b == '"' || b == '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(b)
+			case b == '\n':
+				buf.WriteString(`\n`)
+			case b == '\r':
+				buf.WriteString(`\r`)
+			case b == '\t':
+				buf.WriteString(`\t`)
+			case b < 0x20 || (escapeHTML && (b == '<' || b == '>' || b == '&')):
+				buf.WriteString(`\u00`)
+				buf.WriteByte(_hexDigits[b>>4])
+				buf.WriteByte(_hexDigits[b&0xf])
+			default:
+				buf.WriteByte(b)
+			}
+			idx++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[idx:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buf.WriteString(`\ufffd`)
+		case r == '\u2028' || r == '\u2029':
+			// 与 encoding/json 保持一致，避免在 JavaScript 中被当作换行
+			buf.WriteString(`\u202`)
+			buf.WriteByte(_hexDigits[r&0xf])
+		default:
+			buf.WriteString(s[idx : idx+size])
+		}
+		idx += size
+	}
+	buf.WriteByte('"')
+}
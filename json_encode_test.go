@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type JSONEncodeTest struct {
+	suite.Suite
+}
+
+func TestJSONEncodeTest(t *testing.T) {
+	suite.Run(t, new(JSONEncodeTest))
+}
+
+type encodeLink struct {
+	URL   string            `json:"url"`
+	Title string            `json:"title"`
+	Tags  []string          `json:"tags"`
+	Meta  map[string]string `json:"meta"`
+	Owner *encodeLink       `json:"owner"`
+}
+
+func (j *JSONEncodeTest) TestGopherunJSON_EncodeWithOptions_case1() {
+	link := encodeLink{URL: "https://example.com/?a=1&b=<2>", Title: "demo"}
+
+	// 零值选项：紧凑输出，不转义 HTML
+	jsonStr, err := JSON.EncodeToJSONStrWithOptions(link, JSONEncodeOptions{})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"url":"https://example.com/?a=1&b=<2>","title":"demo","tags":null,"meta":null,"owner":null}`, jsonStr)
+
+	jsonStr, err = JSON.EncodeToJSONStrWithOptions(link, JSONEncodeOptions{EscapeHTML: true})
+	require.Nil(j.T(), err)
+	require.Contains(j.T(), jsonStr, `a=1\u0026b=\u003c2\u003e`)
+}
+
+func (j *JSONEncodeTest) TestGopherunJSON_EncodeWithOptions_case2() {
+	link := encodeLink{
+		URL:   "https://example.com/?a=1&b=2",
+		Tags:  []string{},
+		Meta:  map[string]string{"z": "1", "a": ""},
+		Owner: &encodeLink{Title: "owner", Meta: map[string]string{}},
+	}
+
+	// 排序并全局忽略空字段，清理后变空的对象也会被删除
+	jsonStr, err := JSON.EncodeToJSONStrWithOptions(link, JSONEncodeOptions{SortKeys: true, OmitEmpty: true})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"meta":{"z":"1"},"owner":{"title":"owner"},"url":"https://example.com/?a=1&b=2"}`, jsonStr)
+
+	// 数组元素不受 OmitEmpty 影响，数字保持原样
+	jsonStr, err = JSON.EncodeToJSONStrWithOptions([]interface{}{nil, "", 9007199254740993, 1.5e300, map[string]interface{}{"a": nil}}, JSONEncodeOptions{OmitEmpty: true})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `[null,"",9007199254740993,1.5e+300,{}]`, jsonStr)
+}
+
+func (j *JSONEncodeTest) TestGopherunJSON_EncodeWithOptions_case3() {
+	data := map[string]interface{}{"b": []int{1, 2}, "a": "x"}
+
+	jsonStr, err := JSON.EncodeToJSONStrWithOptions(data, JSONEncodeOptions{Prefix: "//", Indent: "\t"})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "{\n//\t\"a\": \"x\",\n//\t\"b\": [\n//\t\t1,\n//\t\t2\n//\t]\n//}", jsonStr)
+
+	// 排序与缩进、转义可以组合使用
+	jsonStr, err = JSON.EncodeToJSONStrWithOptions(struct {
+		Z string `json:"z"`
+		A string `json:"a"`
+	}{Z: "<\"\\\n \x01>", A: "中文"}, JSONEncodeOptions{Indent: " ", SortKeys: true, EscapeHTML: true})
+	require.Nil(j.T(), err)
+	expected, _ := json.MarshalIndent(map[string]string{"z": "<\"\\\n \x01>", "a": "中文"}, "", " ")
+	require.Equal(j.T(), string(expected), jsonStr)
+}
+
+func (j *JSONEncodeTest) TestGopherunJSON_EncodeWithOptions_case4() {
+	_, err := JSON.EncodeWithOptions(make(chan int), JSONEncodeOptions{SortKeys: true})
+	require.NotNil(j.T(), err)
+
+	jsonStr, err := JSON.EncodeToJSONStrWithOptions(make(chan int), JSONEncodeOptions{})
+	require.NotNil(j.T(), err)
+	require.Equal(j.T(), "", jsonStr)
+}
+
+func (j *JSONEncodeTest) TestGopherunJSON_Pretty() {
+	pretty := JSON.Pretty(map[string]string{"url": "a?b=1&c=2"})
+	require.Equal(j.T(), "{\n  \"url\": \"a?b=1&c=2\"\n}", pretty)
+
+	// 编码失败时退回到 %+v
+	pretty = JSON.Pretty(struct{ C chan int }{})
+	require.True(j.T(), strings.HasPrefix(pretty, "{C:"))
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"fmt"
+	"os"
+)
+
+// _defaultBackupSuffix 备份文件的默认后缀
+const _defaultBackupSuffix = ".bak"
+
+// JSONFileOptions WriteJSONFile 的配置，零值表示紧凑输出且不备份
+type JSONFileOptions struct {
+	// JSONEncodeOptions 编码配置，设置 Indent 即可格式化输出
+	JSONEncodeOptions
+
+	// Backup 为 true 时，覆盖已存在的文件前将旧文件复制为 path + BackupSuffix
+	Backup bool
+
+	// BackupSuffix 备份文件的后缀，默认 ".bak"
+	BackupSuffix string
+}
+
+// ReadJSONFile 读取 path 并解码到 obj，压缩文件（如 .gz）会被透明解压；错误信息中带有文件路径
+func (i GopherunJSON) ReadJSONFile(path string, obj interface{}) error {
+	data, err := File.ReadAll(path)
+	if err != nil {
+		return err
+	}
+	if err = i.Decode(data, obj); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// WriteJSONFile 将 obj 编码后通过 WriteFileSafer 原子写入 path，写入的内容以换行符结尾；
+// 扩展名为 .gz 等压缩格式时压缩后写入
+func (i GopherunJSON) WriteJSONFile(path string, obj interface{}, perm os.FileMode, opts JSONFileOptions) error {
+	data, err := i.EncodeWithOptions(obj, opts.JSONEncodeOptions)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if opts.Backup {
+		if err = backupFile(path, opts.BackupSuffix, perm); err != nil {
+			return err
+		}
+	}
+
+	return File.WriteFileSaferCompressed(path, data, perm)
+}
+
+// backupFile 将已存在的 path 原样（压缩文件保持压缩）复制为 path + suffix，文件不存在时不做任何事；
+// suffix 为空时使用 _defaultBackupSuffix。WriteJSONFile 与 JSONStore 共用
+func backupFile(path, suffix string, perm os.FileMode) error {
+	if suffix == "" {
+		suffix = _defaultBackupSuffix
+	}
+	previous, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return File.WriteFileSafer(path+suffix, previous, perm)
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type JSONFileTest struct {
+	suite.Suite
+}
+
+func TestJSONFileTest(t *testing.T) {
+	suite.Run(t, new(JSONFileTest))
+}
+
+type jsonFileConfig struct {
+	Name  string `json:"name"`
+	Ports []int  `json:"ports"`
+}
+
+func (j *JSONFileTest) TestGopherunJSON_WriteJSONFile_case1() {
+	path := filepath.Join(j.T().TempDir(), "config.json")
+	cfg := jsonFileConfig{Name: "<app>", Ports: []int{80, 443}}
+
+	require.Nil(j.T(), JSON.WriteJSONFile(path, cfg, 0600, JSONFileOptions{JSONEncodeOptions: JSONEncodeOptions{Indent: "  "}}))
+	data, err := os.ReadFile(path)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "{\n  \"name\": \"<app>\",\n  \"ports\": [\n    80,\n    443\n  ]\n}\n", string(data))
+	info, err := os.Stat(path)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), os.FileMode(0600), info.Mode().Perm())
+
+	var loaded jsonFileConfig
+	require.Nil(j.T(), JSON.ReadJSONFile(path, &loaded))
+	require.Equal(j.T(), cfg, loaded)
+
+	// 覆盖时备份旧文件
+	cfg.Name = "app2"
+	require.Nil(j.T(), JSON.WriteJSONFile(path, cfg, 0600, JSONFileOptions{Backup: true}))
+	backup, err := os.ReadFile(path + ".bak")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), data, backup)
+	data, err = os.ReadFile(path)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "{\"name\":\"app2\",\"ports\":[80,443]}\n", string(data))
+
+	// 文件不存在时不生成备份
+	other := filepath.Join(filepath.Dir(path), "other.json")
+	require.Nil(j.T(), JSON.WriteJSONFile(other, cfg, 0644, JSONFileOptions{Backup: true, BackupSuffix: ".old"}))
+	require.False(j.T(), File.IsExists(other+".old"))
+	require.Nil(j.T(), JSON.WriteJSONFile(other, cfg, 0644, JSONFileOptions{Backup: true, BackupSuffix: ".old"}))
+	require.True(j.T(), File.IsExists(other+".old"))
+}
+
+func (j *JSONFileTest) TestGopherunJSON_WriteJSONFile_case2() {
+	// .gz 文件压缩写入，读取时透明解压
+	path := filepath.Join(j.T().TempDir(), "config.json.gz")
+	cfg := jsonFileConfig{Name: strings.Repeat("a", 1000)}
+
+	require.Nil(j.T(), JSON.WriteJSONFile(path, cfg, 0644, JSONFileOptions{}))
+	data, err := os.ReadFile(path)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), CompressionGzip, File.CompressionByMagic(data))
+	require.Less(j.T(), len(data), 1000)
+
+	var loaded jsonFileConfig
+	require.Nil(j.T(), JSON.ReadJSONFile(path, &loaded))
+	require.Equal(j.T(), cfg, loaded)
+
+	require.NotNil(j.T(), JSON.WriteJSONFile(path, make(chan int), 0644, JSONFileOptions{}))
+}
+
+func (j *JSONFileTest) TestGopherunJSON_ReadJSONFile() {
+	dir := j.T().TempDir()
+
+	var cfg jsonFileConfig
+	err := JSON.ReadJSONFile(filepath.Join(dir, "missing.json"), &cfg)
+	require.True(j.T(), os.IsNotExist(err))
+
+	path := filepath.Join(dir, "bad.json")
+	require.Nil(j.T(), os.WriteFile(path, []byte("{\n  \"ports\": [80, \"443\"]\n}"), 0644))
+	err = JSON.ReadJSONFile(path, &cfg)
+	var decodeErr *JSONDecodeError
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.Equal(j.T(), "/ports/1", decodeErr.Path)
+	require.True(j.T(), strings.HasPrefix(err.Error(), path+": line 2, column 17"))
+}
@@ -0,0 +1,356 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidFlatKey 扁平化的键无法解析，或多个键之间存在冲突（如同时存在 "a" 与 "a.b"）
+var ErrInvalidFlatKey = errors.New("invalid flattened JSON key")
+
+// JSONArrayIndexStyle 扁平化时数组下标的写法
+type JSONArrayIndexStyle string
+
+const (
+	JSONArrayIndexBracket JSONArrayIndexStyle = "bracket" // a.b[0].c
+	JSONArrayIndexDot     JSONArrayIndexStyle = "dot"     // a.b.0.c
+)
+
+// JSONFlattenOptions Flatten 与 Unflatten 的配置，两者需使用相同的配置才能还原
+type JSONFlattenOptions struct {
+	// Separator 对象键之间的分隔符，默认 "."
+	Separator string
+
+	// ArrayIndex 数组下标的写法，默认 JSONArrayIndexBracket
+	ArrayIndex JSONArrayIndexStyle
+
+	// MaxDepth 大于 0 时最多展开的层数，更深的对象与数组作为值原样保留
+	MaxDepth int
+}
+
+func (o JSONFlattenOptions) withDefaults() (JSONFlattenOptions, error) {
+	if o.Separator == "" {
+		o.Separator = "."
+	}
+	if strings.ContainsAny(o.Separator, `\[]`) {
+		return o, fmt.Errorf("%w: separator %q must not contain '\\', '[' or ']'", ErrInvalidFlatKey, o.Separator)
+	}
+	switch o.ArrayIndex {
+	case "":
+		o.ArrayIndex = JSONArrayIndexBracket
+	case JSONArrayIndexBracket, JSONArrayIndexDot:
+	default:
+		return o, fmt.Errorf("%w: unknown array index style %q", ErrInvalidFlatKey, o.ArrayIndex)
+	}
+	return o, nil
+}
+
+// Flatten 将嵌套的 JSON 展开为一层，键为 a.b[0].c 形式的路径，值为叶子节点，用于导出 CSV 或写入键值存储。
+// doc 可以是原始 JSON（[]byte 或 json.RawMessage），也可以是任意可编码的 Go 值，但顶层必须是对象或数组。
+// 数字保留为 json.Number，嵌套的空对象与空数组作为值保留，因此 Unflatten 可以原样还原；
+// 例外是顶层的空对象或空数组，展开后为空 map，Unflatten 将其还原为空对象。
+// 键中的 \、方括号与分隔符中出现的任一字符（以 dot 写法时还有纯数字的键）使用 \ 转义，
+// 因此 "a:" 这样以多字符分隔符的一部分结尾的键也能正确还原。
+func (i GopherunJSON) Flatten(doc interface{}, opts JSONFlattenOptions) (map[string]interface{}, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	tree, err := normalizeJSONValue(doc)
+	if err != nil {
+		return nil, err
+	}
+	var size int
+	switch value := tree.(type) {
+	case map[string]interface{}:
+		size = len(value)
+	case []interface{}:
+		size = len(value)
+	default:
+		return nil, fmt.Errorf("%w: cannot flatten JSON %s", ErrJSONTypeMismatch, jsonValueKind(tree))
+	}
+
+	flat := map[string]interface{}{}
+	if size == 0 {
+		// 顶层的空容器没有可用的键，与 {"": {}} 无法区分，因此展开为空 map
+		return flat, nil
+	}
+	flattenJSONValue(flat, "", tree, 0, opts)
+	return flat, nil
+}
+
+func flattenJSONValue(flat map[string]interface{}, prefix string, node interface{}, depth int, opts JSONFlattenOptions) {
+	expand := opts.MaxDepth <= 0 || depth < opts.MaxDepth
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if len(value) > 0 && expand {
+			for key, child := range value {
+				name := escapeFlatKey(key, opts)
+				if depth > 0 {
+					name = prefix + opts.Separator + name
+				}
+				flattenJSONValue(flat, name, child, depth+1, opts)
+			}
+			return
+		}
+	case []interface{}:
+		if len(value) > 0 && expand {
+			for idx, child := range value {
+				var name string
+				switch {
+				case opts.ArrayIndex == JSONArrayIndexDot && depth > 0:
+					name = prefix + opts.Separator + strconv.Itoa(idx)
+				case opts.ArrayIndex == JSONArrayIndexDot:
+					name = strconv.Itoa(idx)
+				default:
+					name = prefix + "[" + strconv.Itoa(idx) + "]"
+				}
+				flattenJSONValue(flat, name, child, depth+1, opts)
+			}
+			return
+		}
+	}
+	flat[prefix] = node
+}
+
+func escapeFlatKey(key string, opts JSONFlattenOptions) string {
+	var builder strings.Builder
+	if opts.ArrayIndex == JSONArrayIndexDot && isFlatIndex(key) {
+		// 纯数字的键与数组下标区分
+		builder.WriteByte('\\')
+	}
+	for idx := 0; idx < len(key); {
+		r, size := utf8.DecodeRuneInString(key[idx:])
+		// 逐个转义分隔符中的字符，而不只是完整的分隔符，否则 "a:" 与分隔符 "::" 相连时无法区分
+		if r == '\\' || r == '[' || r == ']' || strings.ContainsRune(opts.Separator, r) {
+			builder.WriteByte('\\')
+		}
+		builder.WriteString(key[idx : idx+size])
+		idx += size
+	}
+	return builder.String()
+}
+
+// flatToken 扁平化键中的一段，index >= 0 时表示数组下标
+type flatToken struct {
+	key   string
+	index int
+}
+
+// parseFlatKey 按 opts 将扁平化的键拆分为对象键与数组下标
+func parseFlatKey(key string, opts JSONFlattenOptions) ([]flatToken, error) {
+	var (
+		tokens  []flatToken
+		current strings.Builder
+		escaped bool // 当前片段中有转义字符，不作为数组下标
+		pending = true
+	)
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %q: %s", ErrInvalidFlatKey, key, reason)
+	}
+	finish := func() {
+		token := flatToken{key: current.String(), index: -1}
+		if opts.ArrayIndex == JSONArrayIndexDot && !escaped && isFlatIndex(token.key) {
+			token.index, _ = strconv.Atoi(token.key)
+		}
+		tokens = append(tokens, token)
+		current.Reset()
+		escaped, pending = false, false
+	}
+
+	for pos := 0; pos < len(key); {
+		switch {
+		case key[pos] == '\\':
+			if pos+1 >= len(key) {
+				return nil, invalid("dangling escape")
+			}
+			_, size := utf8.DecodeRuneInString(key[pos+1:])
+			current.WriteString(key[pos+1 : pos+1+size])
+			pos += 1 + size
+			escaped, pending = true, true
+		case strings.HasPrefix(key[pos:], opts.Separator):
+			if pending {
+				finish()
+			}
+			pos += len(opts.Separator)
+			pending = true
+		case key[pos] == '[' && opts.ArrayIndex == JSONArrayIndexBracket:
+			// 顶层数组的键以 [ 开头，前面没有对象键
+			if pending && (current.Len() > 0 || escaped || (pos > 0 && strings.HasSuffix(key[:pos], opts.Separator))) {
+				finish()
+			}
+			end := strings.IndexByte(key[pos:], ']')
+			if end < 0 {
+				return nil, invalid("missing ']'")
+			}
+			number := key[pos+1 : pos+end]
+			if !isFlatIndex(number) {
+				return nil, invalid(fmt.Sprintf("%q is not an array index", number))
+			}
+			index, err := strconv.Atoi(number)
+			if err != nil {
+				return nil, invalid(fmt.Sprintf("%q is not an array index", number))
+			}
+			tokens = append(tokens, flatToken{index: index})
+			pos += end + 1
+			pending = false
+			if pos < len(key) && key[pos] != '[' && !strings.HasPrefix(key[pos:], opts.Separator) {
+				return nil, invalid("expected separator after ']'")
+			}
+		case key[pos] == ']' && opts.ArrayIndex == JSONArrayIndexBracket:
+			return nil, invalid("unexpected ']'")
+		default:
+			current.WriteByte(key[pos])
+			pos++
+			pending = true
+		}
+	}
+	if pending {
+		finish()
+	}
+	return tokens, nil
+}
+
+// isFlatIndex 是否为不带前导零的非负整数
+func isFlatIndex(s string) bool {
+	return s != "" && strings.TrimLeft(s, "0123456789") == "" && (s == "0" || s[0] != '0')
+}
+
+// flatNode Unflatten 过程中的节点，leaf 为 true 时表示已经设置了值
+type flatNode struct {
+	leaf     bool
+	value    interface{}
+	array    bool
+	keys     map[string]*flatNode
+	elements map[int]*flatNode
+}
+
+// _maxFlatSparseSlots Unflatten 时所有数组中缺失下标的总数上限，超过时返回 ErrInvalidFlatKey
+const _maxFlatSparseSlots = 1024
+
+// Unflatten 将 Flatten 得到的扁平结构还原为嵌套的 JSON 树（对象为 map[string]interface{}，数组为 []interface{}），
+// opts 需与 Flatten 时相同。数组中缺失的下标填充为 nil；同一路径既是值又是对象或数组时返回 ErrInvalidFlatKey。
+// 为避免 a[1000000000] 这样的键占用大量内存，所有数组的总长度不能超过键的数量加上 1024。
+func (i GopherunJSON) Unflatten(flat map[string]interface{}, opts JSONFlattenOptions) (interface{}, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	// 按键排序处理，保证冲突时的错误信息稳定
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	root := &flatNode{}
+	for _, key := range keys {
+		tokens, err := parseFlatKey(key, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		node := root
+		for _, token := range tokens {
+			if node.leaf {
+				return nil, fmt.Errorf("%w: %q conflicts with a value set by a shorter key", ErrInvalidFlatKey, key)
+			}
+			node = node.child(token)
+			if node == nil {
+				return nil, fmt.Errorf("%w: %q mixes array indexes and object keys at the same level", ErrInvalidFlatKey, key)
+			}
+		}
+		if node.keys != nil || node.elements != nil || node.leaf {
+			return nil, fmt.Errorf("%w: %q conflicts with a longer key", ErrInvalidFlatKey, key)
+		}
+		node.leaf, node.value = true, flat[key]
+	}
+
+	if !root.leaf && root.keys == nil && root.elements == nil {
+		return map[string]interface{}{}, nil
+	}
+	budget := len(flat) + _maxFlatSparseSlots
+	return root.build(&budget)
+}
+
+// child 返回 token 对应的子节点，不存在时创建；对象与数组混用时返回 nil
+func (n *flatNode) child(token flatToken) *flatNode {
+	if token.index >= 0 {
+		if n.keys != nil {
+			return nil
+		}
+		if n.elements == nil {
+			n.array, n.elements = true, map[int]*flatNode{}
+		}
+		if _, ok := n.elements[token.index]; !ok {
+			n.elements[token.index] = &flatNode{}
+		}
+		return n.elements[token.index]
+	}
+
+	if n.elements != nil {
+		return nil
+	}
+	if n.keys == nil {
+		n.keys = map[string]*flatNode{}
+	}
+	if _, ok := n.keys[token.key]; !ok {
+		n.keys[token.key] = &flatNode{}
+	}
+	return n.keys[token.key]
+}
+
+// build 构造节点对应的值，budget 为剩余可分配的数组元素数量
+func (n *flatNode) build(budget *int) (interface{}, error) {
+	switch {
+	case n.leaf:
+		return n.value, nil
+	case n.array:
+		length := 0
+		for index := range n.elements {
+			if index >= *budget {
+				return nil, fmt.Errorf("%w: array index %d exceeds the limit", ErrInvalidFlatKey, index)
+			}
+			if index+1 > length {
+				length = index + 1
+			}
+		}
+		*budget -= length
+		array := make([]interface{}, length)
+		for index, child := range n.elements {
+			value, err := child.build(budget)
+			if err != nil {
+				return nil, err
+			}
+			array[index] = value
+		}
+		return array, nil
+	default:
+		object := make(map[string]interface{}, len(n.keys))
+		for key, child := range n.keys {
+			value, err := child.build(budget)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = value
+		}
+		return object, nil
+	}
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type JSONFlattenTest struct {
+	suite.Suite
+}
+
+func TestJSONFlattenTest(t *testing.T) {
+	suite.Run(t, new(JSONFlattenTest))
+}
+
+const _testFlattenDoc = `{
+	"a": {"b": [{"c": 1}, {"c": 2.50, "d": null}], "e": true},
+	"name": "x",
+	"empty": {"obj": {}, "arr": []},
+	"odd.key": {"[0]": "v", "back\\slash": 1, "0": "zero", "": "blank"}
+}`
+
+func (j *JSONFlattenTest) TestGopherunJSON_Flatten_case1() {
+	flat, err := JSON.Flatten([]byte(_testFlattenDoc), JSONFlattenOptions{})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), map[string]interface{}{
+		"a.b[0].c":             json.Number("1"),
+		"a.b[1].c":             json.Number("2.50"),
+		"a.b[1].d":             nil,
+		"a.e":                  true,
+		"name":                 "x",
+		"empty.obj":            map[string]interface{}{},
+		"empty.arr":            []interface{}{},
+		`odd\.key.\[0\]`:       "v",
+		`odd\.key.back\\slash`: json.Number("1"),
+		`odd\.key.0`:           "zero",
+		`odd\.key.`:            "blank",
+	}, flat)
+
+	doc, err := JSON.Unflatten(flat, JSONFlattenOptions{})
+	require.Nil(j.T(), err)
+	equal, err := JSON.Equal(doc, []byte(_testFlattenDoc))
+	require.Nil(j.T(), err)
+	require.True(j.T(), equal)
+	// 数字保持原始文本
+	require.Equal(j.T(), json.Number("2.50"), doc.(map[string]interface{})["a"].(map[string]interface{})["b"].([]interface{})[1].(map[string]interface{})["c"])
+}
+
+func (j *JSONFlattenTest) TestGopherunJSON_Flatten_case2() {
+	opts := JSONFlattenOptions{Separator: "/", ArrayIndex: JSONArrayIndexDot}
+	flat, err := JSON.Flatten([]byte(_testFlattenDoc), opts)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), json.Number("1"), flat["a/b/0/c"])
+	require.Equal(j.T(), "zero", flat[`odd.key/\0`])
+	require.Equal(j.T(), "v", flat[`odd.key/\[0\]`])
+
+	doc, err := JSON.Unflatten(flat, opts)
+	require.Nil(j.T(), err)
+	equal, err := JSON.Equal(doc, []byte(_testFlattenDoc))
+	require.Nil(j.T(), err)
+	require.True(j.T(), equal)
+
+	// 顶层数组
+	for _, style := range []JSONArrayIndexStyle{JSONArrayIndexBracket, JSONArrayIndexDot} {
+		opts = JSONFlattenOptions{ArrayIndex: style}
+		flat, err = JSON.Flatten([]interface{}{map[string]interface{}{"": map[string]interface{}{"x": 1}}, []int{2}}, opts)
+		require.Nil(j.T(), err)
+		doc, err = JSON.Unflatten(flat, opts)
+		require.Nil(j.T(), err)
+		require.Equal(j.T(), []interface{}{
+			map[string]interface{}{"": map[string]interface{}{"x": json.Number("1")}},
+			[]interface{}{json.Number("2")},
+		}, doc, style)
+	}
+}
+
+func (j *JSONFlattenTest) TestGopherunJSON_Flatten_case3() {
+	type Item struct {
+		ID   int               `json:"id"`
+		Tags map[string]string `json:"tags"`
+	}
+	flat, err := JSON.Flatten(struct {
+		Items []Item `json:"items"`
+	}{Items: []Item{{ID: 1, Tags: map[string]string{"k": "v"}}}}, JSONFlattenOptions{MaxDepth: 2})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), map[string]interface{}{
+		"items[0]": map[string]interface{}{"id": json.Number("1"), "tags": map[string]interface{}{"k": "v"}},
+	}, flat)
+
+	doc, err := JSON.Unflatten(flat, JSONFlattenOptions{MaxDepth: 2})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), map[string]interface{}{"items": []interface{}{flat["items[0]"]}}, doc)
+
+	_, err = JSON.Flatten([]byte(`1`), JSONFlattenOptions{})
+	require.True(j.T(), errors.Is(err, ErrJSONTypeMismatch))
+	_, err = JSON.Flatten([]byte(`{`), JSONFlattenOptions{})
+	require.NotNil(j.T(), err)
+	_, err = JSON.Flatten([]byte(`{}`), JSONFlattenOptions{Separator: "["})
+	require.True(j.T(), errors.Is(err, ErrInvalidFlatKey))
+	_, err = JSON.Flatten([]byte(`{}`), JSONFlattenOptions{ArrayIndex: "paren"})
+	require.True(j.T(), errors.Is(err, ErrInvalidFlatKey))
+}
+
+func (j *JSONFlattenTest) TestGopherunJSON_Flatten_case4() {
+	// 键以多字符分隔符的一部分结尾或包含分隔符时可以原样还原
+	cases := []struct {
+		separator string
+		doc       string
+	}{
+		{"::", `{"a:":{"b":[1]},":x":{":":2},"::":{"a::b":3}}`},
+		{"->", `{"a-":{">b":1},"-":{">":{"-":2}}}`},
+		{"→", `{"a→":{"→b":1},"é":{"→→":2}}`},
+	}
+	for _, c := range cases {
+		opts := JSONFlattenOptions{Separator: c.separator}
+		flat, err := JSON.Flatten([]byte(c.doc), opts)
+		require.Nil(j.T(), err, c.separator)
+		doc, err := JSON.Unflatten(flat, opts)
+		require.Nil(j.T(), err, c.separator)
+		equal, err := JSON.Equal(doc, []byte(c.doc))
+		require.Nil(j.T(), err)
+		require.True(j.T(), equal, "%s: %v", c.separator, doc)
+	}
+
+	flat, err := JSON.Flatten([]byte(`{"a:":{"b":[1]}}`), JSONFlattenOptions{Separator: "::"})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), map[string]interface{}{`a\:::b[0]`: json.Number("1")}, flat)
+}
+
+func (j *JSONFlattenTest) TestGopherunJSON_Flatten_emptyRoot() {
+	// 顶层的空对象与空数组展开为空 map，还原为空对象
+	for _, doc := range []string{`{}`, `[]`} {
+		flat, err := JSON.Flatten([]byte(doc), JSONFlattenOptions{})
+		require.Nil(j.T(), err)
+		require.Equal(j.T(), map[string]interface{}{}, flat, doc)
+		restored, err := JSON.Unflatten(flat, JSONFlattenOptions{})
+		require.Nil(j.T(), err)
+		require.Equal(j.T(), map[string]interface{}{}, restored, doc)
+	}
+
+	// 空字符串的键不与顶层的空容器混淆
+	for _, doc := range []string{`{"":1}`, `{"":{}}`, `{"":[]}`} {
+		flat, err := JSON.Flatten([]byte(doc), JSONFlattenOptions{})
+		require.Nil(j.T(), err)
+		require.Len(j.T(), flat, 1, doc)
+		restored, err := JSON.Unflatten(flat, JSONFlattenOptions{})
+		require.Nil(j.T(), err)
+		equal, err := JSON.Equal(restored, []byte(doc))
+		require.Nil(j.T(), err)
+		require.True(j.T(), equal, "%s: %v", doc, restored)
+	}
+}
+
+func (j *JSONFlattenTest) TestGopherunJSON_Unflatten() {
+	// 来自 CSV 等外部数据：缺失的下标填充为 nil
+	doc, err := JSON.Unflatten(map[string]interface{}{"a[2]": "c", "a[0]": "a", "b.c": 1}, JSONFlattenOptions{})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), map[string]interface{}{"a": []interface{}{"a", nil, "c"}, "b": map[string]interface{}{"c": 1}}, doc)
+
+	// 缺失的下标总数不超过上限时允许
+	doc, err = JSON.Unflatten(map[string]interface{}{"a[1000]": 1}, JSONFlattenOptions{})
+	require.Nil(j.T(), err)
+	require.Len(j.T(), doc.(map[string]interface{})["a"], 1001)
+
+	// 上限是所有数组共享的
+	sparse := map[string]interface{}{}
+	for idx := 0; idx < 10; idx++ {
+		sparse[fmt.Sprintf("a%d[200]", idx)] = idx
+	}
+	_, err = JSON.Unflatten(sparse, JSONFlattenOptions{})
+	require.True(j.T(), errors.Is(err, ErrInvalidFlatKey), "%v", err)
+	_, err = JSON.Unflatten(map[string]interface{}{"a.99999999999999999999": 1}, JSONFlattenOptions{ArrayIndex: JSONArrayIndexDot})
+	require.True(j.T(), errors.Is(err, ErrInvalidFlatKey), "%v", err)
+
+	doc, err = JSON.Unflatten(map[string]interface{}{}, JSONFlattenOptions{})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), map[string]interface{}{}, doc)
+
+	cases := []map[string]interface{}{
+		{"a": 1, "a.b": 2},
+		{"a[0]": 1, "a.b": 2},
+		{"a[0]": 1, "a[0].b": 2},
+		{"a[x]": 1},
+		{"a[01]": 1},
+		{"a[0": 1},
+		{"a]": 1},
+		{"a[0]b": 1},
+		{`a\`: 1},
+		// 过于稀疏的下标
+		{"a[99999999999999]": 1},
+		{"a[1000000000]": 1},
+		{"a[9223372036854775807]": 1},
+		{"a[99999999999999999999]": 1},
+	}
+	for _, c := range cases {
+		_, err = JSON.Unflatten(c, JSONFlattenOptions{})
+		require.True(j.T(), errors.Is(err, ErrInvalidFlatKey), "%v: %v", c, err)
+	}
+}
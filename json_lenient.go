@@ -0,0 +1,291 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JSONLenientError 宽松解码的错误及其在原始文本中的位置
+type JSONLenientError struct {
+	Line   int   // 行号，从 1 开始
+	Column int   // 列号（字节），从 1 开始
+	Offset int64 // 相对于原始文本开头的字节偏移量
+	Err    error // 原始错误
+}
+
+func (e *JSONLenientError) Error() string {
+	return fmt.Sprintf("line %d, column %d (offset %d): %v", e.Line, e.Column, e.Offset, e.Err)
+}
+
+func (e *JSONLenientError) Unwrap() error {
+	return e.Err
+}
+
+// ToStandardJSON 将人工编辑的宽松 JSON（JSONC / JSON5 子集）转换为标准 JSON，支持：
+// // 与 /* */ 注释、对象与数组末尾多余的逗号、单引号字符串、不加引号的对象键（标识符形式），以及开头的 UTF-8 BOM。
+// 出错时返回 *JSONLenientError，行列号指向原始文本。
+func (i GopherunJSON) ToStandardJSON(data []byte) ([]byte, error) {
+	converter := &lenientConverter{src: data}
+	if err := converter.convert(); err != nil {
+		return nil, err
+	}
+	return converter.out.Bytes(), nil
+}
+
+// DecodeLenient 先将宽松 JSON 转换为标准 JSON 再解码到 obj，适用于配置文件。
+// 语法错误与类型错误均返回 *JSONLenientError，行列号指向原始文本而不是转换后的文本。
+func (i GopherunJSON) DecodeLenient(data []byte, obj interface{}) error {
+	converter := &lenientConverter{src: data}
+	if err := converter.convert(); err != nil {
+		return err
+	}
+
+	// 直接使用 JSONCodec，位置信息由转换前后的对应关系换算
+	err := i.Codec().Unmarshal(converter.out.Bytes(), obj)
+	if err == nil {
+		return nil
+	}
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		// SyntaxError.Offset 指向出错字符之后；输入不完整时指向原始文本末尾
+		offset := int(syntaxErr.Offset) - 1
+		if offset < 0 || syntaxErr.Error() == "unexpected end of JSON input" {
+			offset = converter.out.Len()
+		}
+		return converter.errorAt(converter.sourceOffset(offset, false), err)
+	case errors.As(err, &typeErr):
+		// UnmarshalTypeError.Offset 指向值之后，定位到该值的起始位置
+		return converter.errorAt(converter.sourceOffset(int(typeErr.Offset)-1, true), err)
+	default:
+		return err
+	}
+}
+
+// DecodeLenientByJSONStr 与 DecodeLenient 相同，输入为字符串
+func (i GopherunJSON) DecodeLenientByJSONStr(jsonStr string, obj interface{}) error {
+	return i.DecodeLenient([]byte(jsonStr), obj)
+}
+
+// lenientMark 转换后文本与原始文本的偏移量对应关系，每个输出片段记录一次
+type lenientMark struct {
+	out, in int
+}
+
+type lenientConverter struct {
+	src   []byte
+	pos   int
+	out   bytes.Buffer
+	marks []lenientMark
+}
+
+func (c *lenientConverter) emit(in int, b []byte) {
+	c.marks = append(c.marks, lenientMark{out: c.out.Len(), in: in})
+	c.out.Write(b)
+}
+
+func (c *lenientConverter) convert() error {
+	c.pos = 0
+	if bytes.HasPrefix(c.src, []byte("\xef\xbb\xbf")) {
+		c.pos = 3
+	}
+
+	for c.pos < len(c.src) {
+		start := c.pos
+		ch := c.src[c.pos]
+		switch {
+		case ch == '/' && c.pos+1 < len(c.src) && (c.src[c.pos+1] == '/' || c.src[c.pos+1] == '*'):
+			end, err := c.skipComment(c.pos)
+			if err != nil {
+				return err
+			}
+			c.pos = end
+		case ch == '"':
+			end, err := c.scanString(c.pos, '"')
+			if err != nil {
+				return err
+			}
+			c.emit(start, c.src[start:end])
+			c.pos = end
+		case ch == '\'':
+			if err := c.convertSingleQuoted(); err != nil {
+				return err
+			}
+		case ch == ',':
+			// 末尾多余的逗号直接丢弃
+			if next := c.nextSignificant(c.pos + 1); next != '}' && next != ']' {
+				c.emit(start, c.src[start:start+1])
+			}
+			c.pos++
+		case isLenientSpace(ch):
+			for c.pos < len(c.src) && isLenientSpace(c.src[c.pos]) {
+				c.pos++
+			}
+			c.emit(start, c.src[start:c.pos])
+		case ch == '-' || ch == '+' || ch == '.' || (ch >= '0' && ch <= '9'):
+			for c.pos < len(c.src) && (isLenientIdentChar(c.src[c.pos]) || strings.IndexByte("+-.", c.src[c.pos]) >= 0) {
+				c.pos++
+			}
+			c.emit(start, c.src[start:c.pos])
+		case isLenientIdentChar(ch):
+			for c.pos < len(c.src) && isLenientIdentChar(c.src[c.pos]) {
+				c.pos++
+			}
+			if c.nextSignificant(c.pos) == ':' {
+				// 不加引号的对象键
+				c.emit(start, []byte(`"`+string(c.src[start:c.pos])+`"`))
+			} else {
+				c.emit(start, c.src[start:c.pos])
+			}
+		default:
+			c.emit(start, c.src[start:start+1])
+			c.pos++
+		}
+	}
+	return nil
+}
+
+// skipComment 返回注释之后的位置，// 注释不包含行尾的换行符
+func (c *lenientConverter) skipComment(start int) (int, error) {
+	if c.src[start+1] == '/' {
+		end := bytes.IndexByte(c.src[start:], '\n')
+		if end < 0 {
+			return len(c.src), nil
+		}
+		return start + end, nil
+	}
+	end := bytes.Index(c.src[start+2:], []byte("*/"))
+	if end < 0 {
+		return 0, c.errorAt(start, errors.New("unterminated block comment"))
+	}
+	return start + 2 + end + 2, nil
+}
+
+// scanString 返回字符串结束引号之后的位置
+func (c *lenientConverter) scanString(start int, quote byte) (int, error) {
+	for pos := start + 1; pos < len(c.src); pos++ {
+		switch c.src[pos] {
+		case '\\':
+			pos++
+		case quote:
+			return pos + 1, nil
+		}
+	}
+	return 0, c.errorAt(start, errors.New("unterminated string"))
+}
+
+// convertSingleQuoted 将单引号字符串转换为双引号字符串：\' 还原为 '，" 转义为 \"，其余转义原样保留
+func (c *lenientConverter) convertSingleQuoted() error {
+	start := c.pos
+	end, err := c.scanString(start, '\'')
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	for pos := start + 1; pos < end-1; pos++ {
+		switch ch := c.src[pos]; ch {
+		case '\\':
+			pos++
+			if c.src[pos] == '\'' {
+				buf.WriteByte('\'')
+			} else {
+				buf.WriteByte('\\')
+				buf.WriteByte(c.src[pos])
+			}
+		case '"':
+			buf.WriteString(`\"`)
+		default:
+			buf.WriteByte(ch)
+		}
+	}
+	buf.WriteByte('"')
+
+	c.emit(start, buf.Bytes())
+	c.pos = end
+	return nil
+}
+
+// nextSignificant 返回 from 之后第一个非空白、非注释的字符，没有时返回 0
+func (c *lenientConverter) nextSignificant(from int) byte {
+	pos := from
+	for pos < len(c.src) {
+		ch := c.src[pos]
+		switch {
+		case isLenientSpace(ch):
+			pos++
+		case ch == '/' && pos+1 < len(c.src) && (c.src[pos+1] == '/' || c.src[pos+1] == '*'):
+			end, err := c.skipComment(pos)
+			if err != nil {
+				return 0
+			}
+			pos = end
+		default:
+			return ch
+		}
+	}
+	return 0
+}
+
+// sourceOffset 将转换后文本中的偏移量换算为原始文本中的偏移量，tokenStart 为 true 时返回所在片段的起始位置
+func (c *lenientConverter) sourceOffset(out int, tokenStart bool) int {
+	if len(c.marks) == 0 {
+		return len(c.src)
+	}
+	if out >= c.out.Len() {
+		// 输入不完整，指向原始文本末尾
+		return len(c.src)
+	}
+	idx := sort.Search(len(c.marks), func(idx int) bool {
+		return c.marks[idx].out > out
+	}) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	mark := c.marks[idx]
+	if tokenStart {
+		return mark.in
+	}
+	return mark.in + out - mark.out
+}
+
+func (c *lenientConverter) errorAt(offset int, err error) *JSONLenientError {
+	line, lineStart := 1, 0
+	for idx := 0; idx < offset && idx < len(c.src); idx++ {
+		if c.src[idx] == '\n' {
+			line++
+			lineStart = idx + 1
+		}
+	}
+	return &JSONLenientError{Line: line, Column: offset - lineStart + 1, Offset: int64(offset), Err: err}
+}
+
+func isLenientSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+// isLenientIdentChar 标识符字符：字母、数字、_、$ 与非 ASCII 字符
+func isLenientIdentChar(ch byte) bool {
+	return ch == '_' || ch == '$' || ch >= 0x80 ||
+		(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+}
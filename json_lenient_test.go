@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type JSONLenientTest struct {
+	suite.Suite
+}
+
+func TestJSONLenientTest(t *testing.T) {
+	suite.Run(t, new(JSONLenientTest))
+}
+
+type lenientTestConfig struct {
+	Name    string            `json:"name"`
+	Port    int               `json:"port"`
+	Debug   bool              `json:"debug"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+	Comment string            `json:"comment"`
+}
+
+func (j *JSONLenientTest) TestGopherunJSON_DecodeLenient_case1() {
+	data := "\xef\xbb\xbf" + `// 服务配置
+{
+	/* 服务名，
+	   可以包含 // 与 /* */
+	name: 'gopher\'s "app"',
+	$port: 1,
+	port: 8080, // 端口
+	"debug": true,
+	tags: ['a', "b", 'c\n',],
+	labels: {
+		env_1: 'dev',
+		'a-b': "http://example.com/*x*/",
+	},
+	comment: "it's // not a comment",
+}
+`
+	var cfg lenientTestConfig
+	require.Nil(j.T(), JSON.DecodeLenient([]byte(data), &cfg))
+	require.Equal(j.T(), lenientTestConfig{
+		Name:    `gopher's "app"`,
+		Port:    8080,
+		Debug:   true,
+		Tags:    []string{"a", "b", "c\n"},
+		Labels:  map[string]string{"env_1": "dev", "a-b": "http://example.com/*x*/"},
+		Comment: "it's // not a comment",
+	}, cfg)
+
+	// 标准 JSON 原样可用
+	var obj map[string]interface{}
+	require.Nil(j.T(), JSON.DecodeLenientByJSONStr(`{"a":[1,-2.5e3,true,null]}`, &obj))
+	require.Equal(j.T(), map[string]interface{}{"a": []interface{}{1.0, -2500.0, true, nil}}, obj)
+}
+
+func (j *JSONLenientTest) TestGopherunJSON_ToStandardJSON() {
+	data, err := JSON.ToStandardJSON([]byte(`{a: 1, /* x */ 'b': [true, null,], c$: 'x"y', // end
+}`))
+	require.Nil(j.T(), err)
+	require.True(j.T(), json.Valid(data), string(data))
+	require.JSONEq(j.T(), `{"a":1,"b":[true,null],"c$":"x\"y"}`, string(data))
+
+	// 值位置上的标识符不加引号
+	data, err = JSON.ToStandardJSON([]byte(`[true, false, null]`))
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `[true, false, null]`, string(data))
+
+	_, err = JSON.ToStandardJSON([]byte("{\n  /* open"))
+	var lenientErr *JSONLenientError
+	require.True(j.T(), errors.As(err, &lenientErr))
+	require.Equal(j.T(), 2, lenientErr.Line)
+	require.Equal(j.T(), 3, lenientErr.Column)
+	require.Equal(j.T(), "line 2, column 3 (offset 4): unterminated block comment", err.Error())
+
+	_, err = JSON.ToStandardJSON([]byte("{\n a: 'abc}"))
+	require.True(j.T(), errors.As(err, &lenientErr))
+	require.Equal(j.T(), 2, lenientErr.Line)
+	require.Equal(j.T(), 5, lenientErr.Column)
+}
+
+func (j *JSONLenientTest) TestGopherunJSON_DecodeLenient_case2() {
+	var (
+		cfg        lenientTestConfig
+		lenientErr *JSONLenientError
+	)
+
+	// 语法错误：行列号指向原始文本（注释与引号转换不影响位置）
+	data := "{\n  // comment\n  name: 'x', /* c */ port: 1 2,\n}"
+	err := JSON.DecodeLenient([]byte(data), &cfg)
+	require.True(j.T(), errors.As(err, &lenientErr))
+	require.Equal(j.T(), 3, lenientErr.Line)
+	require.Equal(j.T(), 30, lenientErr.Column)
+	var syntaxErr *json.SyntaxError
+	require.True(j.T(), errors.As(err, &syntaxErr))
+
+	// 类型错误：定位到值的起始位置
+	data = "{\n  name: 'x',\n  tags: ['a', 'b'],\n  port: 'not a number',\n}"
+	err = JSON.DecodeLenient([]byte(data), &cfg)
+	require.True(j.T(), errors.As(err, &lenientErr))
+	require.Equal(j.T(), 4, lenientErr.Line)
+	require.Equal(j.T(), 9, lenientErr.Column)
+	var typeErr *json.UnmarshalTypeError
+	require.True(j.T(), errors.As(err, &typeErr))
+
+	// 输入不完整时指向末尾
+	data = "{\n  name: 'x',"
+	err = JSON.DecodeLenient([]byte(data), &cfg)
+	require.True(j.T(), errors.As(err, &lenientErr))
+	require.Equal(j.T(), 2, lenientErr.Line)
+	require.Equal(j.T(), 13, lenientErr.Column)
+
+	err = JSON.DecodeLenient([]byte(`// only comment`), &cfg)
+	require.True(j.T(), errors.As(err, &lenientErr))
+
+	err = JSON.DecodeLenient([]byte(`'abc`), &cfg)
+	require.True(j.T(), errors.As(err, &lenientErr))
+
+	// 非语法、类型错误原样返回
+	err = JSON.DecodeLenient([]byte(`{}`), cfg)
+	require.NotNil(j.T(), err)
+	require.False(j.T(), errors.As(err, &lenientErr))
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// JSONLinesWriter 以 JSON Lines 格式向 io.Writer 写入记录，每条记录占一行。
+// 写入经过缓冲，结束时必须调用 Flush；非并发安全。
+type JSONLinesWriter struct {
+	// FlushEvery 每写入多少条记录自动 Flush 一次，<= 0 时仅在缓冲区满或手动调用时写出
+	FlushEvery int
+
+	w       *bufio.Writer
+	pending int
+}
+
+// NewLinesWriter 创建一个写入 w 的 JSONLinesWriter
+func (i GopherunJSON) NewLinesWriter(w io.Writer) *JSONLinesWriter {
+	return &JSONLinesWriter{w: bufio.NewWriter(w)}
+}
+
+// Write 编码 record 并写入一行
+func (w *JSONLinesWriter) Write(record interface{}) error {
+	line, err := encodeJSONLine(record)
+	if err != nil {
+		return err
+	}
+	if _, err = w.w.Write(line); err != nil {
+		return err
+	}
+
+	w.pending++
+	if w.FlushEvery > 0 && w.pending >= w.FlushEvery {
+		return w.Flush()
+	}
+	return nil
+}
+
+// Flush 将缓冲区中的数据写出到底层 io.Writer
+func (w *JSONLinesWriter) Flush() error {
+	w.pending = 0
+	return w.w.Flush()
+}
+
+// AppendLinesFile 将 records 以 JSON Lines 格式追加到文件末尾，文件不存在时以 0644 权限创建。
+// 所有记录会先完成编码，编码失败时不写入任何数据。
+// .gz 文件会追加一个新的 gzip 成员，ReadLinesFile 可以连续读取；其他压缩格式无法追加，返回 ErrCompressionUnsupported。
+func (i GopherunJSON) AppendLinesFile(path string, records ...interface{}) (err error) {
+	compression := File.CompressionByExt(path)
+	if compression != CompressionNone && compression != CompressionGzip {
+		return fmt.Errorf("%w: cannot append to %s file %s", ErrCompressionUnsupported, compression, path)
+	}
+
+	var buf bytes.Buffer
+	for _, record := range records {
+		line, err := encodeJSONLine(record)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+	}
+	data := buf.Bytes()
+	if len(data) > 0 {
+		if data, err = File.Compress(data, compression); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	_, err = f.Write(data)
+	return err
+}
+
+// ReadLinesFile 读取 JSON Lines 文件中的全部记录，压缩文件（如 .jsonl.gz）会被透明解压
+func (i GopherunJSON) ReadLinesFile(path string) ([]json.RawMessage, error) {
+	return JSONReadLinesFile[json.RawMessage](path)
+}
+
+// JSONReadLinesFile 读取 JSON Lines 文件并将每条记录解码为 T，压缩文件（如 .jsonl.gz）会被透明解压
+func JSONReadLinesFile[T any](path string) ([]T, error) {
+	reader, err := File.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var records []T
+	err = JSONStreamLines(reader, func(record T) error {
+		records = append(records, record)
+		return nil
+	}, JSONStreamOptions{})
+	return records, err
+}
+
+// encodeJSONLine 编码一条记录并追加换行符
+func encodeJSONLine(record interface{}) ([]byte, error) {
+	line, err := JSON.Encode(record)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
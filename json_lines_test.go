@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type JSONLinesTest struct {
+	BaseTest
+}
+
+func TestJSONLinesTest(t *testing.T) {
+	suite.Run(t, new(JSONLinesTest))
+}
+
+func (j *JSONLinesTest) TestJSONLinesWriter_case1() {
+	var buf bytes.Buffer
+	writer := JSON.NewLinesWriter(&buf)
+
+	require.Nil(j.T(), writer.Write(streamUser{Name: "zhangsan", Age: 12}))
+	require.Nil(j.T(), writer.Write(map[string]string{"url": "a?b=1&c=2"}))
+	require.Equal(j.T(), 0, buf.Len(), "should be buffered before Flush")
+
+	require.Nil(j.T(), writer.Flush())
+	require.Equal(j.T(), "{\"name\":\"zhangsan\",\"age\":12}\n{\"url\":\"a?b=1\\u0026c=2\"}\n", buf.String())
+}
+
+func (j *JSONLinesTest) TestJSONLinesWriter_case2() {
+	var buf bytes.Buffer
+	writer := JSON.NewLinesWriter(&buf)
+	writer.FlushEvery = 2
+
+	require.Nil(j.T(), writer.Write(1))
+	require.Equal(j.T(), 0, buf.Len())
+	require.Nil(j.T(), writer.Write(2))
+	require.Equal(j.T(), "1\n2\n", buf.String())
+
+	// 编码失败不会写入
+	require.NotNil(j.T(), writer.Write(make(chan int)))
+	require.Nil(j.T(), writer.Flush())
+	require.Equal(j.T(), "1\n2\n", buf.String())
+}
+
+func (j *JSONLinesTest) TestGopherunJSON_AppendLinesFile() {
+	path := filepath.Join(j.T().TempDir(), "events.jsonl")
+
+	require.Nil(j.T(), JSON.AppendLinesFile(path, streamUser{Name: "zhangsan", Age: 12}))
+	require.Nil(j.T(), JSON.AppendLinesFile(path, streamUser{Name: "lisi", Age: 13}, streamUser{Name: "wangwu", Age: 14}))
+
+	// 任意一条编码失败时整体不写入
+	require.NotNil(j.T(), JSON.AppendLinesFile(path, streamUser{Name: "zhaoliu"}, make(chan int)))
+
+	users, err := JSONReadLinesFile[streamUser](path)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []streamUser{{"zhangsan", 12}, {"lisi", 13}, {"wangwu", 14}}, users)
+
+	raws, err := JSON.ReadLinesFile(path)
+	require.Nil(j.T(), err)
+	require.Len(j.T(), raws, 3)
+	require.Equal(j.T(), `{"name":"lisi","age":13}`, string(raws[1]))
+
+	_, err = JSON.ReadLinesFile(filepath.Join(j.T().TempDir(), "not-exist.jsonl"))
+	require.True(j.T(), os.IsNotExist(err))
+}
+
+func (j *JSONLinesTest) TestGopherunJSON_AppendLinesFile_compressed() {
+	tempDir := j.T().TempDir()
+
+	// .gz 文件追加新的 gzip 成员，而不是写入明文
+	path := filepath.Join(tempDir, "events.jsonl.gz")
+	require.Nil(j.T(), File.WriteFileSaferCompressed(path, []byte("{\"name\":\"zhangsan\",\"age\":12}\n"), 0644))
+	require.Nil(j.T(), JSON.AppendLinesFile(path, streamUser{Name: "lisi", Age: 13}))
+	require.Nil(j.T(), JSON.AppendLinesFile(path))
+
+	users, err := JSONReadLinesFile[streamUser](path)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []streamUser{{"zhangsan", 12}, {"lisi", 13}}, users)
+
+	// 新建的 .gz 文件同样是压缩的
+	path = filepath.Join(tempDir, "new.jsonl.gz")
+	require.Nil(j.T(), JSON.AppendLinesFile(path, streamUser{Name: "wangwu", Age: 14}))
+	data, err := os.ReadFile(path)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), CompressionGzip, File.CompressionByMagic(data))
+
+	// 无法追加的压缩格式返回错误，且不修改文件
+	for _, name := range []string{"events.jsonl.zlib", "events.jsonl.bz2"} {
+		path = filepath.Join(tempDir, name)
+		err = JSON.AppendLinesFile(path, streamUser{Name: "zhaoliu"})
+		require.True(j.T(), errors.Is(err, ErrCompressionUnsupported), "%s: %v", name, err)
+		require.NoFileExists(j.T(), path)
+	}
+}
+
+func (j *JSONLinesTest) TestJSONReadLinesFile() {
+	path := filepath.Join(j.T().TempDir(), "events.jsonl.gz")
+	require.Nil(j.T(), File.WriteFileSaferCompressed(path, []byte("{\"name\":\"zhangsan\",\"age\":12}\n"), 0644))
+
+	users, err := JSONReadLinesFile[streamUser](path)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []streamUser{{"zhangsan", 12}}, users)
+}
@@ -0,0 +1,312 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const _maxJSONNumberExponent = 10000
+
+var (
+	// ErrInvalidNumber 不是合法的 JSON 数字
+	ErrInvalidNumber = errors.New("invalid JSON number")
+
+	// ErrNumberNotInteger 数字带有小数部分，无法转换为整数
+	ErrNumberNotInteger = errors.New("JSON number is not an integer")
+
+	// ErrNumberOutOfRange 数字超出目标类型的范围
+	ErrNumberOutOfRange = errors.New("JSON number out of range")
+)
+
+// JSONDecodeOptions 解码配置，零值时 DecodeWithOptions 等同于 Decode
+type JSONDecodeOptions struct {
+	// UseNumber 为 true 时解码到 interface{} 的数字保留为 json.Number，而不是转换为 float64，
+	// 超过 2^53 的整数（如雪花 ID）不会丢失精度；此时使用 encoding/json 解码，不使用 SetCodec 设置的引擎
+	UseNumber bool
+}
+
+// DecodeWithOptions 按 opts 将 data 解码到 obj，与 Decode 一样拒绝第一个值之后的多余数据、包装错误位置并处理多态类型
+func (i GopherunJSON) DecodeWithOptions(data []byte, obj interface{}, opts JSONDecodeOptions) error {
+	if !opts.UseNumber {
+		return i.Decode(data, obj)
+	}
+
+	if decodesJSONUnions(obj) {
+		return decodeJSONUnions(data, 0, len(data), "", obj, unmarshalJSONUseNumber)
+	}
+	if err := unmarshalJSONUseNumber(data, obj); err != nil {
+		return newJSONDecodeError(data, err)
+	}
+	return nil
+}
+
+// unmarshalJSONUseNumber 与 json.Unmarshal 相同，但解码到 interface{} 的数字保留为 json.Number
+func unmarshalJSONUseNumber(data []byte, obj interface{}) error {
+	if !json.Valid(data) {
+		// 语法错误（包括多余数据）与 json.Unmarshal 相同
+		return json.Unmarshal(data, new(json.RawMessage))
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(obj)
+}
+
+// DecodeByJSONStrWithOptions 按 opts 将 JSON 字符串解码到 obj
+func (i GopherunJSON) DecodeByJSONStrWithOptions(jsonStr string, obj interface{}, opts JSONDecodeOptions) error {
+	return i.DecodeWithOptions([]byte(jsonStr), obj, opts)
+}
+
+// NumberToInt64 将 json.Number 精确转换为 int64，"1e3"、"10.0" 等整数值同样接受；
+// 带小数部分时返回 ErrNumberNotInteger，超出范围时返回 ErrNumberOutOfRange，而不是静默截断
+func (i GopherunJSON) NumberToInt64(number json.Number) (int64, error) {
+	value, err := i.NumberToBigInt(number)
+	if err != nil {
+		return 0, err
+	}
+	if !value.IsInt64() {
+		return 0, fmt.Errorf("%w: %s overflows int64", ErrNumberOutOfRange, number)
+	}
+	return value.Int64(), nil
+}
+
+// NumberToUint64 将 json.Number 精确转换为 uint64，规则与 NumberToInt64 相同
+func (i GopherunJSON) NumberToUint64(number json.Number) (uint64, error) {
+	value, err := i.NumberToBigInt(number)
+	if err != nil {
+		return 0, err
+	}
+	if !value.IsUint64() {
+		return 0, fmt.Errorf("%w: %s overflows uint64", ErrNumberOutOfRange, number)
+	}
+	return value.Uint64(), nil
+}
+
+// NumberToBigInt 将 json.Number 精确转换为 big.Int，带小数部分时返回 ErrNumberNotInteger
+func (i GopherunJSON) NumberToBigInt(number json.Number) (*big.Int, error) {
+	decimal, err := parseJSONDecimal(number)
+	if err != nil {
+		return nil, err
+	}
+	if len(decimal.digits) > decimal.point {
+		return nil, fmt.Errorf("%w: %s", ErrNumberNotInteger, number)
+	}
+
+	value, _ := new(big.Int).SetString(decimal.String(), 10)
+	return value, nil
+}
+
+// NumberToDecimalString 将 json.Number 转换为不含指数的十进制字符串，结果是精确的，
+// 并去掉多余的前导零与小数末尾的零，例如 "1.50e3" 转换为 "1500"，"-0.000120" 转换为 "-0.00012"
+func (i GopherunJSON) NumberToDecimalString(number json.Number) (string, error) {
+	decimal, err := parseJSONDecimal(number)
+	if err != nil {
+		return "", err
+	}
+	return decimal.String(), nil
+}
+
+// jsonDecimal JSON 数字的精确表示：值为 0.digits × 10^point
+type jsonDecimal struct {
+	negative bool
+	digits   string // 有效数字，不含前导零与末尾的零，值为 0 时为空
+	point    int    // 小数点在 digits 中的位置
+}
+
+func (d jsonDecimal) String() string {
+	if d.digits == "" {
+		return "0"
+	}
+
+	var text string
+	switch {
+	case d.point <= 0:
+		text = "0." + strings.Repeat("0", -d.point) + d.digits
+	case d.point >= len(d.digits):
+		text = d.digits + strings.Repeat("0", d.point-len(d.digits))
+	default:
+		text = d.digits[:d.point] + "." + d.digits[d.point:]
+	}
+	if d.negative {
+		text = "-" + text
+	}
+	return text
+}
+
+// parseJSONDecimal 按 JSON 数字语法解析 number
+func parseJSONDecimal(number json.Number) (jsonDecimal, error) {
+	var (
+		s       = string(number)
+		pos     int
+		decimal jsonDecimal
+	)
+	invalid := func() error {
+		return fmt.Errorf("%w: %q", ErrInvalidNumber, s)
+	}
+	scanDigits := func() string {
+		start := pos
+		for pos < len(s) && s[pos] >= '0' && s[pos] <= '9' {
+			pos++
+		}
+		return s[start:pos]
+	}
+
+	if pos < len(s) && s[pos] == '-' {
+		decimal.negative = true
+		pos++
+	}
+	intPart := scanDigits()
+	if intPart == "" || (len(intPart) > 1 && intPart[0] == '0') {
+		return jsonDecimal{}, invalid()
+	}
+	var fracPart string
+	if pos < len(s) && s[pos] == '.' {
+		pos++
+		if fracPart = scanDigits(); fracPart == "" {
+			return jsonDecimal{}, invalid()
+		}
+	}
+	exponent := 0
+	if pos < len(s) && (s[pos] == 'e' || s[pos] == 'E') {
+		pos++
+		start := pos
+		if pos < len(s) && (s[pos] == '+' || s[pos] == '-') {
+			pos++
+		}
+		if scanDigits() == "" {
+			return jsonDecimal{}, invalid()
+		}
+		var err error
+		if exponent, err = strconv.Atoi(s[start:pos]); err != nil {
+			exponent = _maxJSONNumberExponent + 1
+			if s[start] == '-' {
+				exponent = -exponent
+			}
+		}
+	}
+	if pos != len(s) {
+		return jsonDecimal{}, invalid()
+	}
+
+	digits := intPart + fracPart
+	point := len(intPart)
+	trimmed := strings.TrimLeft(digits, "0")
+	point -= len(digits) - len(trimmed)
+	decimal.digits = strings.TrimRight(trimmed, "0")
+	if decimal.digits == "" {
+		// 0、-0、0e999 等均为 0
+		return jsonDecimal{}, nil
+	}
+	if exponent > _maxJSONNumberExponent || exponent < -_maxJSONNumberExponent {
+		return jsonDecimal{}, fmt.Errorf("%w: exponent of %s is too large", ErrNumberOutOfRange, s)
+	}
+	decimal.point = point + exponent
+	return decimal, nil
+}
+
+// quoteJSONInt64Values 对照 Go 值的类型，将编码结果中 int、int64、uint、uint64 类型的数字替换为字符串
+func quoteJSONInt64Values(node interface{}, v reflect.Value, fieldCache map[reflect.Type]map[string][]int) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return node
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return node
+	}
+	if v.Type().Implements(_jsonMarshalerType) || reflect.PtrTo(v.Type()).Implements(_jsonMarshalerType) ||
+		v.Type().Implements(_textMarshalerType) || reflect.PtrTo(v.Type()).Implements(_textMarshalerType) {
+		return node
+	}
+
+	switch value := node.(type) {
+	case json.Number:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+			return value.String()
+		}
+	case []interface{}:
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			for idx := range value {
+				if idx < v.Len() {
+					value[idx] = quoteJSONInt64Values(value[idx], v.Index(idx), fieldCache)
+				}
+			}
+		}
+	case *orderedJSONObject:
+		switch v.Kind() {
+		case reflect.Struct:
+			fields, ok := fieldCache[v.Type()]
+			if !ok {
+				fields = map[string][]int{}
+				for _, field := range jsonStructFields(v.Type()) {
+					fields[field.name] = field.index
+				}
+				fieldCache[v.Type()] = fields
+			}
+			for idx, key := range value.keys {
+				index, ok := fields[key]
+				if !ok {
+					continue
+				}
+				if fieldValue, err := v.FieldByIndexErr(index); err == nil {
+					value.values[idx] = quoteJSONInt64Values(value.values[idx], fieldValue, fieldCache)
+				}
+			}
+		case reflect.Map:
+			for idx, key := range value.keys {
+				mapKey, ok := jsonMapKey(v.Type().Key(), key)
+				if !ok {
+					continue
+				}
+				if element := v.MapIndex(mapKey); element.IsValid() {
+					value.values[idx] = quoteJSONInt64Values(value.values[idx], element, fieldCache)
+				}
+			}
+		}
+	}
+	return node
+}
+
+// jsonMapKey 将编码后的对象键还原为 map 的键，不支持实现 encoding.TextMarshaler 的键类型
+func jsonMapKey(keyType reflect.Type, key string) (reflect.Value, bool) {
+	if keyType.Implements(_textMarshalerType) || reflect.PtrTo(keyType).Implements(_textMarshalerType) {
+		return reflect.Value{}, false
+	}
+	switch keyType.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(key).Convert(keyType), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		return reflect.ValueOf(n).Convert(keyType), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		return reflect.ValueOf(n).Convert(keyType), true
+	default:
+		return reflect.Value{}, false
+	}
+}
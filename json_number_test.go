@@ -0,0 +1,220 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+type JSONNumberTest struct {
+	suite.Suite
+}
+
+func TestJSONNumberTest(t *testing.T) {
+	suite.Run(t, new(JSONNumberTest))
+}
+
+func (j *JSONNumberTest) TestGopherunJSON_DecodeWithOptions() {
+	data := []byte(`{"id":9007199254740993,"price":0.1}`)
+
+	var obj map[string]interface{}
+	require.Nil(j.T(), JSON.DecodeWithOptions(data, &obj, JSONDecodeOptions{UseNumber: true}))
+	require.Equal(j.T(), json.Number("9007199254740993"), obj["id"])
+	require.Equal(j.T(), json.Number("0.1"), obj["price"])
+
+	// 零值与 Decode 行为一致
+	obj = nil
+	require.Nil(j.T(), JSON.DecodeByJSONStrWithOptions(string(data), &obj, JSONDecodeOptions{}))
+	require.Equal(j.T(), float64(9007199254740992), obj["id"])
+
+	type Order struct {
+		ID    int64       `json:"id"`
+		Extra interface{} `json:"extra"`
+	}
+	var order Order
+	require.Nil(j.T(), JSON.DecodeWithOptions([]byte(`{"id":9007199254740993,"extra":[1]}`), &order, JSONDecodeOptions{UseNumber: true}))
+	require.Equal(j.T(), Order{ID: 9007199254740993, Extra: []interface{}{json.Number("1")}}, order)
+
+	require.NotNil(j.T(), JSON.DecodeWithOptions([]byte(`{} {}`), &obj, JSONDecodeOptions{UseNumber: true}))
+	require.NotNil(j.T(), JSON.DecodeWithOptions([]byte(`{"id":1`), &obj, JSONDecodeOptions{UseNumber: true}))
+	// 空输入与 Decode 返回相同类型的错误
+	for _, data := range []string{``, ` `} {
+		err := JSON.DecodeWithOptions([]byte(data), &obj, JSONDecodeOptions{UseNumber: true})
+		var decodeErr *JSONDecodeError
+		require.True(j.T(), errors.As(err, &decodeErr), "%q: %v", data, err)
+		require.Equal(j.T(), JSON.Decode([]byte(data), &obj).Error(), err.Error())
+	}
+
+	// 零值使用 SetCodec 设置的引擎，错误与 Decode 相同
+	JSON.SetCodec(failingCodec{})
+	defer JSON.SetCodec(nil)
+	require.EqualError(j.T(), JSON.DecodeWithOptions(data, &obj, JSONDecodeOptions{}), "unmarshal failed")
+	JSON.SetCodec(nil)
+	require.Equal(j.T(), JSON.Decode([]byte(`{} {}`), &obj).Error(), JSON.DecodeWithOptions([]byte(`{} {}`), &obj, JSONDecodeOptions{}).Error())
+}
+
+func (j *JSONNumberTest) TestGopherunJSON_NumberToInt64() {
+	cases := []struct {
+		number   json.Number
+		expected int64
+		target   error
+	}{
+		{"9007199254740993", 9007199254740993, nil},
+		{"-9223372036854775808", math.MinInt64, nil},
+		{"9223372036854775807", math.MaxInt64, nil},
+		{"1e3", 1000, nil},
+		{"12.50e1", 125, nil},
+		{"10.0", 10, nil},
+		{"-0", 0, nil},
+		{"0e99999999999", 0, nil},
+		{"9223372036854775808", 0, ErrNumberOutOfRange},
+		{"1e100", 0, ErrNumberOutOfRange},
+		{"1e99999999999", 0, ErrNumberOutOfRange},
+		{"1.5", 0, ErrNumberNotInteger},
+		{"1e-1", 0, ErrNumberNotInteger},
+		{"", 0, ErrInvalidNumber},
+		{"abc", 0, ErrInvalidNumber},
+		{"01", 0, ErrInvalidNumber},
+		{"1.", 0, ErrInvalidNumber},
+		{"1e", 0, ErrInvalidNumber},
+		{"+1", 0, ErrInvalidNumber},
+		{"1 ", 0, ErrInvalidNumber},
+	}
+	for _, c := range cases {
+		value, err := JSON.NumberToInt64(c.number)
+		if c.target != nil {
+			require.True(j.T(), errors.Is(err, c.target), "%s: %v", c.number, err)
+			continue
+		}
+		require.Nil(j.T(), err, c.number)
+		require.Equal(j.T(), c.expected, value, c.number)
+	}
+}
+
+func (j *JSONNumberTest) TestGopherunJSON_NumberToUint64() {
+	value, err := JSON.NumberToUint64("18446744073709551615")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), uint64(math.MaxUint64), value)
+
+	_, err = JSON.NumberToUint64("18446744073709551616")
+	require.True(j.T(), errors.Is(err, ErrNumberOutOfRange))
+	_, err = JSON.NumberToUint64("-1")
+	require.True(j.T(), errors.Is(err, ErrNumberOutOfRange))
+	_, err = JSON.NumberToUint64("0.5")
+	require.True(j.T(), errors.Is(err, ErrNumberNotInteger))
+}
+
+func (j *JSONNumberTest) TestGopherunJSON_NumberToBigInt() {
+	value, err := JSON.NumberToBigInt("-123456789012345678901234567890e2")
+	require.Nil(j.T(), err)
+	expected, _ := new(big.Int).SetString("-12345678901234567890123456789000", 10)
+	require.Equal(j.T(), 0, expected.Cmp(value))
+
+	_, err = JSON.NumberToBigInt("1.000001")
+	require.True(j.T(), errors.Is(err, ErrNumberNotInteger))
+	_, err = JSON.NumberToBigInt("x")
+	require.True(j.T(), errors.Is(err, ErrInvalidNumber))
+}
+
+func (j *JSONNumberTest) TestGopherunJSON_NumberToDecimalString() {
+	cases := map[json.Number]string{
+		"0":                      "0",
+		"-0.0":                   "0",
+		"1.50e3":                 "1500",
+		"-0.000120":              "-0.00012",
+		"1e-7":                   "0.0000001",
+		"123.456e-1":             "12.3456",
+		"0.1":                    "0.1",
+		"100":                    "100",
+		"9007199254740993":       "9007199254740993",
+		"1.2345678901234567e+30": "1234567890123456700000000000000",
+	}
+	for number, expected := range cases {
+		value, err := JSON.NumberToDecimalString(number)
+		require.Nil(j.T(), err, number)
+		require.Equal(j.T(), expected, value, number)
+	}
+
+	_, err := JSON.NumberToDecimalString("1e-99999")
+	require.True(j.T(), errors.Is(err, ErrNumberOutOfRange))
+	_, err = JSON.NumberToDecimalString("NaN")
+	require.True(j.T(), errors.Is(err, ErrInvalidNumber))
+}
+
+type numberTestID int64
+
+type numberTestItem struct {
+	ID    numberTestID `json:"id"`
+	Count int32        `json:"count"`
+}
+
+type numberTestOrder struct {
+	ID       int64                       `json:"id"`
+	UserID   *uint64                     `json:"userId"`
+	Quoted   int64                       `json:"quoted,string"`
+	Price    float64                     `json:"price"`
+	Items    []numberTestItem            `json:"items"`
+	Counters map[string]int              `json:"counters"`
+	ByID     map[int64]uint              `json:"byId"`
+	Extra    interface{}                 `json:"extra"`
+	Created  time.Time                   `json:"created"`
+	Timeout  time.Duration               `json:"timeout"`
+	Nested   map[string][]numberTestItem `json:"nested,omitempty"`
+	Raw      json.RawMessage             `json:"raw"`
+}
+
+func (j *JSONNumberTest) TestGopherunJSON_EncodeWithOptions_Int64AsString() {
+	userID := uint64(18446744073709551615)
+	order := numberTestOrder{
+		ID:       9007199254740993,
+		UserID:   &userID,
+		Quoted:   7,
+		Price:    1.5,
+		Items:    []numberTestItem{{ID: 1, Count: 2}},
+		Counters: map[string]int{"a": 1},
+		ByID:     map[int64]uint{42: 3},
+		Extra:    int64(5),
+		Created:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Timeout:  time.Second,
+		Raw:      json.RawMessage(`{"n":1}`),
+	}
+
+	data, err := JSON.EncodeWithOptions(order, JSONEncodeOptions{Int64AsString: true})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"id":"9007199254740993","userId":"18446744073709551615","quoted":"7","price":1.5,"items":[{"id":"1","count":2}],"counters":{"a":"1"},"byId":{"42":"3"},"extra":"5","created":"2025-01-02T03:04:05Z","timeout":"1000000000","raw":{"n":1}}`, string(data))
+
+	// 可与其他选项组合
+	data, err = JSON.EncodeWithOptions(&order, JSONEncodeOptions{Int64AsString: true, SortKeys: true, OmitEmpty: true})
+	require.Nil(j.T(), err)
+	require.Contains(j.T(), string(data), `"byId":{"42":"3"},"counters":{"a":"1"},"created"`)
+
+	data, err = JSON.EncodeWithOptions([]interface{}{int64(1), 2.5, nil, []int{3}}, JSONEncodeOptions{Int64AsString: true})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `["1",2.5,null,["3"]]`, string(data))
+
+	data, err = JSON.EncodeWithOptions(nil, JSONEncodeOptions{Int64AsString: true})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `null`, string(data))
+
+	// 默认不影响数字
+	data, err = JSON.EncodeWithOptions(numberTestItem{ID: 1, Count: 2}, JSONEncodeOptions{})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"id":1,"count":2}`, string(data))
+}
@@ -0,0 +1,404 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPatch JSON Patch 文档格式错误
+	ErrInvalidPatch = errors.New("invalid JSON patch")
+
+	// ErrPatchTestFailed JSON Patch 的 test 操作未通过
+	ErrPatchTestFailed = errors.New("JSON patch test failed")
+)
+
+// JSONPatchOperation JSON Patch（RFC 6902）中的一个操作
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyPatch 将 JSON Patch（RFC 6902）应用到 doc 并返回结果，支持 add、remove、replace、move、copy、test。
+// 操作全部成功才返回新文档，任一操作失败时返回错误（包含失败操作的序号），不会产生部分修改的结果。
+func (i GopherunJSON) ApplyPatch(doc, patch []byte) ([]byte, error) {
+	var operations []JSONPatchOperation
+	if err := i.Decode(patch, &operations); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	tree, err := decodeJSONDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+	for idx, operation := range operations {
+		if tree, err = applyJSONPatchOperation(tree, operation); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %q): %w", idx, operation.Op, operation.Path, err)
+		}
+	}
+	return i.Encode(tree)
+}
+
+// ApplyMergePatch 将 JSON Merge Patch（RFC 7386）应用到 doc 并返回结果
+func (i GopherunJSON) ApplyMergePatch(doc, patch []byte) ([]byte, error) {
+	tree, err := decodeJSONDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+	patchTree, err := decodeJSONDoc(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return i.Encode(mergeJSONPatch(tree, patchTree))
+}
+
+// CreatePatch 比较两个文档，生成将 original 转换为 modified 的 JSON Patch。
+// 数组按下标逐个比较，长度变化体现为尾部的 add 或 remove。
+func (i GopherunJSON) CreatePatch(original, modified []byte) ([]byte, error) {
+	from, err := decodeJSONDoc(original)
+	if err != nil {
+		return nil, err
+	}
+	to, err := decodeJSONDoc(modified)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := []JSONPatchOperation{}
+	if operations, err = diffJSONPatch(operations, "", from, to); err != nil {
+		return nil, err
+	}
+	return i.Encode(operations)
+}
+
+// CreateMergePatch 比较两个文档，生成将 original 转换为 modified 的 JSON Merge Patch。
+// Merge Patch 无法表达数组元素级别的修改与值为 null 的字段，这些情况会整体替换或删除。
+func (i GopherunJSON) CreateMergePatch(original, modified []byte) ([]byte, error) {
+	from, err := decodeJSONDoc(original)
+	if err != nil {
+		return nil, err
+	}
+	to, err := decodeJSONDoc(modified)
+	if err != nil {
+		return nil, err
+	}
+	return i.Encode(diffJSONMergePatch(from, to))
+}
+
+func applyJSONPatchOperation(tree interface{}, operation JSONPatchOperation) (interface{}, error) {
+	path, err := parseJSONPointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := patchOperationValue(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add":
+		return addJSONPatchValue(tree, path, value)
+	case "remove":
+		if len(path) == 0 {
+			return nil, nil
+		}
+		return deleteJSONPointer(tree, path, 0)
+	case "replace":
+		if _, err = JSON.Get(tree, operation.Path); err != nil {
+			return nil, err
+		}
+		return setJSONPointer(tree, path, 0, value, false)
+	case "move", "copy":
+		from, err := parseJSONPointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		source, err := JSON.Get(tree, operation.From)
+		if err != nil {
+			return nil, err
+		}
+		if operation.Op == "copy" {
+			return addJSONPatchValue(tree, path, copyJSONValue(source))
+		}
+		if isJSONPointerPrefix(from, path) && len(from) < len(path) {
+			return nil, fmt.Errorf("%w: cannot move %q into its own child", ErrInvalidPatch, operation.From)
+		}
+		if len(from) > 0 {
+			if tree, err = deleteJSONPointer(tree, from, 0); err != nil {
+				return nil, err
+			}
+		}
+		return addJSONPatchValue(tree, path, source)
+	case "test":
+		current, err := JSON.Get(tree, operation.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonValuesEqual(current, value) {
+			return nil, ErrPatchTestFailed
+		}
+		return tree, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, operation.Op)
+	}
+}
+
+// patchOperationValue 解析 value 成员，add、replace、test 必须提供
+func patchOperationValue(operation JSONPatchOperation) (interface{}, error) {
+	if operation.Value == nil {
+		switch operation.Op {
+		case "add", "replace", "test":
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+		return nil, nil
+	}
+	return decodeJSONDoc(operation.Value)
+}
+
+// addJSONPatchValue 实现 add 语义：父节点必须存在，数组中为插入
+func addJSONPatchValue(tree interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := JSON.Get(tree, buildJSONPointer(path[:len(path)-1]))
+	if err != nil {
+		return nil, err
+	}
+	switch parent.(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		return nil, fmt.Errorf("%w: parent of %q is %s", ErrJSONTypeMismatch, buildJSONPointer(path), jsonValueKind(parent))
+	}
+	return setJSONPointer(tree, path, 0, value, true)
+}
+
+func isJSONPointerPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for idx := range prefix {
+		if prefix[idx] != path[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeJSONPatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergeJSONPatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
+func diffJSONMergePatch(from, to interface{}) interface{} {
+	fromObject, fromOK := from.(map[string]interface{})
+	toObject, toOK := to.(map[string]interface{})
+	if !fromOK || !toOK {
+		return to
+	}
+
+	patch := map[string]interface{}{}
+	for key := range fromObject {
+		if _, ok := toObject[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, toValue := range toObject {
+		fromValue, ok := fromObject[key]
+		if !ok {
+			patch[key] = toValue
+		} else if !jsonValuesEqual(fromValue, toValue) {
+			patch[key] = diffJSONMergePatch(fromValue, toValue)
+		}
+	}
+	return patch
+}
+
+func diffJSONPatch(operations []JSONPatchOperation, path string, from, to interface{}) ([]JSONPatchOperation, error) {
+	if jsonValuesEqual(from, to) {
+		return operations, nil
+	}
+
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		toValue, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, key := range sortedJSONKeys(fromValue) {
+			if _, ok := toValue[key]; !ok {
+				operations = append(operations, JSONPatchOperation{Op: "remove", Path: path + "/" + escapeJSONPointerToken(key)})
+			}
+		}
+		var err error
+		for _, key := range sortedJSONKeys(toValue) {
+			childPath := path + "/" + escapeJSONPointerToken(key)
+			if fromChild, ok := fromValue[key]; ok {
+				operations, err = diffJSONPatch(operations, childPath, fromChild, toValue[key])
+			} else {
+				operations, err = appendJSONPatchOperation(operations, "add", childPath, toValue[key])
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return operations, nil
+	case []interface{}:
+		toValue, ok := to.([]interface{})
+		if !ok {
+			break
+		}
+		common := len(fromValue)
+		if len(toValue) < common {
+			common = len(toValue)
+		}
+		var err error
+		for idx := 0; idx < common; idx++ {
+			if operations, err = diffJSONPatch(operations, path+"/"+strconv.Itoa(idx), fromValue[idx], toValue[idx]); err != nil {
+				return nil, err
+			}
+		}
+		// 从后往前删除，避免下标移动
+		for idx := len(fromValue) - 1; idx >= common; idx-- {
+			operations = append(operations, JSONPatchOperation{Op: "remove", Path: path + "/" + strconv.Itoa(idx)})
+		}
+		for idx := common; idx < len(toValue); idx++ {
+			if operations, err = appendJSONPatchOperation(operations, "add", path+"/-", toValue[idx]); err != nil {
+				return nil, err
+			}
+		}
+		return operations, nil
+	}
+
+	return appendJSONPatchOperation(operations, "replace", path, to)
+}
+
+func appendJSONPatchOperation(operations []JSONPatchOperation, op, path string, value interface{}) ([]JSONPatchOperation, error) {
+	raw, err := JSON.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+	return append(operations, JSONPatchOperation{Op: op, Path: path, Value: raw}), nil
+}
+
+func sortedJSONKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// copyJSONValue 深拷贝已解码的 JSON 树
+func copyJSONValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(typed))
+		for key, child := range typed {
+			copied[key] = copyJSONValue(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(typed))
+		for idx, child := range typed {
+			copied[idx] = copyJSONValue(child)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// jsonValuesEqual 比较两个已解码的 JSON 值：对象忽略键顺序，数字按数值比较（1、1.0、1e0 相等）
+func jsonValuesEqual(a, b interface{}) bool {
+	switch aValue := a.(type) {
+	case map[string]interface{}:
+		bValue, ok := b.(map[string]interface{})
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+		for key, aChild := range aValue {
+			bChild, ok := bValue[key]
+			if !ok || !jsonValuesEqual(aChild, bChild) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bValue, ok := b.([]interface{})
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+		for idx := range aValue {
+			if !jsonValuesEqual(aValue[idx], bValue[idx]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	aNumber, aIsNumber := jsonNumberValue(a)
+	bNumber, bIsNumber := jsonNumberValue(b)
+	if aIsNumber || bIsNumber {
+		return aIsNumber && bIsNumber && aNumber.Cmp(bNumber) == 0
+	}
+	return a == b
+}
+
+// jsonNumberValue 将各种数字表示统一转换为 big.Float 以便精确比较
+func jsonNumberValue(value interface{}) (*big.Float, bool) {
+	var text string
+	switch number := value.(type) {
+	case json.Number:
+		text = number.String()
+	case float64:
+		text = strconv.FormatFloat(number, 'g', -1, 64)
+	case float32:
+		text = strconv.FormatFloat(float64(number), 'g', -1, 32)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		text = fmt.Sprint(number)
+	default:
+		return nil, false
+	}
+
+	parsed, _, err := big.ParseFloat(strings.TrimSpace(text), 10, 256, big.ToNearestEven)
+	if err != nil {
+		return nil, false
+	}
+	return parsed, true
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type JSONPatchTest struct {
+	suite.Suite
+}
+
+func TestJSONPatchTest(t *testing.T) {
+	suite.Run(t, new(JSONPatchTest))
+}
+
+func (j *JSONPatchTest) TestGopherunJSON_ApplyPatch_case1() {
+	// RFC 6902 附录 A 中的示例
+	cases := []struct {
+		doc, patch, expected string
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"foo":null}`, `[{"op":"test","path":"/foo","value":null}]`, `{"foo":null}`},
+		{`{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`, `{"a":{"b":1},"c":{"b":2}}`},
+		{`{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+	}
+	for _, c := range cases {
+		result, err := JSON.ApplyPatch([]byte(c.doc), []byte(c.patch))
+		require.Nil(j.T(), err, c.patch)
+		require.JSONEq(j.T(), c.expected, string(result), c.patch)
+	}
+}
+
+func (j *JSONPatchTest) TestGopherunJSON_ApplyPatch_case2() {
+	doc := []byte(`{"baz":"qux","foo":["a",2,"c"]}`)
+
+	cases := []struct {
+		patch  string
+		target error
+	}{
+		{`[{"op":"test","path":"/baz","value":"bar"}]`, ErrPatchTestFailed},
+		{`[{"op":"add","path":"/baz/bat","value":"qux"}]`, ErrJSONTypeMismatch},
+		{`[{"op":"add","path":"/missing/bat","value":"qux"}]`, ErrPointerNotFound},
+		{`[{"op":"add","path":"/foo/5","value":"qux"}]`, ErrPointerNotFound},
+		{`[{"op":"replace","path":"/missing","value":1}]`, ErrPointerNotFound},
+		{`[{"op":"remove","path":"/missing"}]`, ErrPointerNotFound},
+		{`[{"op":"move","from":"/foo","path":"/foo/0"}]`, ErrInvalidPatch},
+		{`[{"op":"copy","from":"/missing","path":"/x"}]`, ErrPointerNotFound},
+		{`[{"op":"add","path":"/x"}]`, ErrInvalidPatch},
+		{`[{"op":"unknown","path":"/x"}]`, ErrInvalidPatch},
+		{`{"op":"add"}`, ErrInvalidPatch},
+		{`[{"op":"add","path":"x","value":1}]`, ErrInvalidPointer},
+	}
+	for _, c := range cases {
+		_, err := JSON.ApplyPatch(doc, []byte(c.patch))
+		require.True(j.T(), errors.Is(err, c.target), "%s: %v", c.patch, err)
+	}
+
+	// 全部成功或全部失败：前面的操作成功、后面的操作失败时不返回部分结果
+	result, err := JSON.ApplyPatch(doc, []byte(`[{"op":"add","path":"/new","value":1},{"op":"test","path":"/baz","value":"bar"}]`))
+	require.NotNil(j.T(), err)
+	require.Nil(j.T(), result)
+	require.Contains(j.T(), err.Error(), "operation 1 (test \"/baz\")")
+
+	_, err = JSON.ApplyPatch([]byte(`{`), []byte(`[]`))
+	require.NotNil(j.T(), err)
+}
+
+func (j *JSONPatchTest) TestGopherunJSON_ApplyMergePatch() {
+	// RFC 7386 附录 A 中的示例
+	cases := []struct {
+		doc, patch, expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, c := range cases {
+		result, err := JSON.ApplyMergePatch([]byte(c.doc), []byte(c.patch))
+		require.Nil(j.T(), err, c.patch)
+		require.JSONEq(j.T(), c.expected, string(result), c.patch)
+	}
+
+	_, err := JSON.ApplyMergePatch([]byte(`{}`), []byte(`{`))
+	require.True(j.T(), errors.Is(err, ErrInvalidPatch))
+	_, err = JSON.ApplyMergePatch([]byte(`{`), []byte(`{}`))
+	require.NotNil(j.T(), err)
+}
+
+func (j *JSONPatchTest) TestGopherunJSON_CreatePatch() {
+	cases := []struct {
+		original, modified string
+	}{
+		{`{"a":1,"b":{"c":[1,2,3]},"d":"x"}`, `{"a":1.0,"b":{"c":[1,5]},"e":null}`},
+		{`{"a":[1]}`, `{"a":[1,{"b":2},3]}`},
+		{`{"a/b":{"~":1}}`, `{"a/b":{"~":2}}`},
+		{`[1,2]`, `{"a":1}`},
+		{`{"id":9007199254740993}`, `{"id":9007199254740995}`},
+	}
+	for _, c := range cases {
+		patch, err := JSON.CreatePatch([]byte(c.original), []byte(c.modified))
+		require.Nil(j.T(), err)
+
+		result, err := JSON.ApplyPatch([]byte(c.original), patch)
+		require.Nil(j.T(), err, string(patch))
+		require.JSONEq(j.T(), c.modified, string(result), string(patch))
+	}
+
+	patch, err := JSON.CreatePatch([]byte(`{"a":1}`), []byte(`{"a":1.0}`))
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `[]`, string(patch))
+
+	patch, err = JSON.CreatePatch([]byte(`{"a":1,"b":2}`), []byte(`{"b":3}`))
+	require.Nil(j.T(), err)
+	require.JSONEq(j.T(), `[{"op":"remove","path":"/a"},{"op":"replace","path":"/b","value":3}]`, string(patch))
+
+	_, err = JSON.CreatePatch([]byte(`{`), []byte(`{}`))
+	require.NotNil(j.T(), err)
+	_, err = JSON.CreatePatch([]byte(`{}`), []byte(`{`))
+	require.NotNil(j.T(), err)
+}
+
+func (j *JSONPatchTest) TestGopherunJSON_CreateMergePatch() {
+	original := `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`
+	modified := `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`
+
+	patch, err := JSON.CreateMergePatch([]byte(original), []byte(modified))
+	require.Nil(j.T(), err)
+	require.JSONEq(j.T(), `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`, string(patch))
+
+	result, err := JSON.ApplyMergePatch([]byte(original), patch)
+	require.Nil(j.T(), err)
+	require.JSONEq(j.T(), modified, string(result))
+
+	patch, err = JSON.CreateMergePatch([]byte(`{"a":1}`), []byte(`[1]`))
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `[1]`, string(patch))
+
+	_, err = JSON.CreateMergePatch([]byte(`{`), []byte(`{}`))
+	require.NotNil(j.T(), err)
+	_, err = JSON.CreateMergePatch([]byte(`{}`), []byte(`{`))
+	require.NotNil(j.T(), err)
+}
@@ -0,0 +1,792 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrInvalidJSONPath JSONPath 表达式无效
+var ErrInvalidJSONPath = errors.New("invalid JSONPath")
+
+// JSONPathMatch JSONPath 的一个匹配结果
+type JSONPathMatch struct {
+	Path    string      // 规范化路径（RFC 9535），如 $['items'][0]['id']
+	Pointer string      // 同一位置的 JSON Pointer，可直接用于 Get、Set、Delete
+	Value   interface{} // 匹配到的值
+}
+
+// JSONPath 编译后的 JSONPath 表达式（RFC 9535 子集），可并发复用。支持：
+//   - 根 $、子成员 .name / ['name']、通配符 .* / [*]、递归下降 ..name / ..* / ..[...]；
+//   - 数组下标 [0]、负下标 [-1]、切片 [start:end:step]、多选 ['a','b'] / [0,2]；
+//   - 过滤 [?(@.status == 'failed')]（括号可省略），支持 == != < <= > >=、&& || !、括号分组，
+//     操作数可以是 @ / $ 开头的路径、字符串、数字、true、false、null，单独的路径表示“存在”。
+//
+// 对象成员按键的字典序遍历，因此结果顺序是确定的。
+type JSONPath struct {
+	expr     string
+	segments []jsonPathSegment
+}
+
+// CompileJSONPath 编译 JSONPath 表达式
+func (i GopherunJSON) CompileJSONPath(path string) (*JSONPath, error) {
+	parser := &jsonPathParser{src: path}
+	parser.skipSpaces()
+	if !parser.consume('$') {
+		return nil, parser.errorf("must start with '$'")
+	}
+	segments, err := parser.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	parser.skipSpaces()
+	if parser.pos < len(parser.src) {
+		return nil, parser.errorf("unexpected %q", parser.src[parser.pos])
+	}
+	return &JSONPath{expr: path, segments: segments}, nil
+}
+
+// Query 编译 path 并在 doc 上求值，doc 的取值规则与 Get 相同
+func (i GopherunJSON) Query(doc interface{}, path string) ([]JSONPathMatch, error) {
+	compiled, err := i.CompileJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.Query(doc)
+}
+
+// Query 在 doc 上求值，返回全部匹配；没有匹配时返回空切片
+func (p *JSONPath) Query(doc interface{}) ([]JSONPathMatch, error) {
+	tree, err := decodeJSONDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := evalJSONPath(p.segments, []jsonPathNode{{path: "$", value: tree}}, tree)
+	matches := make([]JSONPathMatch, 0, len(nodes))
+	for _, node := range nodes {
+		matches = append(matches, JSONPathMatch{Path: node.path, Pointer: node.pointer, Value: node.value})
+	}
+	return matches, nil
+}
+
+// Values 与 Query 相同，但只返回匹配到的值
+func (p *JSONPath) Values(doc interface{}) ([]interface{}, error) {
+	matches, err := p.Query(doc)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, 0, len(matches))
+	for _, match := range matches {
+		values = append(values, match.Value)
+	}
+	return values, nil
+}
+
+func (p *JSONPath) String() string {
+	return p.expr
+}
+
+type jsonPathNode struct {
+	path    string
+	pointer string
+	value   interface{}
+}
+
+func (n jsonPathNode) member(key string, value interface{}) jsonPathNode {
+	return jsonPathNode{
+		path:    n.path + "[" + quoteJSONPathName(key) + "]",
+		pointer: n.pointer + "/" + escapeJSONPointerToken(key),
+		value:   value,
+	}
+}
+
+func (n jsonPathNode) element(idx int, value interface{}) jsonPathNode {
+	return jsonPathNode{
+		path:    n.path + "[" + strconv.Itoa(idx) + "]",
+		pointer: n.pointer + "/" + strconv.Itoa(idx),
+		value:   value,
+	}
+}
+
+// children 按文档顺序列出子节点，对象成员按键的字典序排列
+func (n jsonPathNode) children() []jsonPathNode {
+	var children []jsonPathNode
+	switch value := n.value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedJSONKeys(value) {
+			children = append(children, n.member(key, value[key]))
+		}
+	case []interface{}:
+		for idx, item := range value {
+			children = append(children, n.element(idx, item))
+		}
+	}
+	return children
+}
+
+type jsonPathSegment struct {
+	descendant bool
+	selectors  []jsonPathSelector
+}
+
+type jsonPathSelector interface {
+	selectNodes(node jsonPathNode, root interface{}, out []jsonPathNode) []jsonPathNode
+}
+
+func evalJSONPath(segments []jsonPathSegment, nodes []jsonPathNode, root interface{}) []jsonPathNode {
+	for _, segment := range segments {
+		var next []jsonPathNode
+		for _, node := range nodes {
+			targets := []jsonPathNode{node}
+			if segment.descendant {
+				targets = appendJSONPathDescendants(nil, node)
+			}
+			for _, target := range targets {
+				for _, selector := range segment.selectors {
+					next = selector.selectNodes(target, root, next)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// appendJSONPathDescendants 先序遍历 node 及其全部后代
+func appendJSONPathDescendants(out []jsonPathNode, node jsonPathNode) []jsonPathNode {
+	out = append(out, node)
+	for _, child := range node.children() {
+		out = appendJSONPathDescendants(out, child)
+	}
+	return out
+}
+
+type jsonPathName string
+
+func (s jsonPathName) selectNodes(node jsonPathNode, _ interface{}, out []jsonPathNode) []jsonPathNode {
+	if object, ok := node.value.(map[string]interface{}); ok {
+		if value, ok := object[string(s)]; ok {
+			out = append(out, node.member(string(s), value))
+		}
+	}
+	return out
+}
+
+type jsonPathWildcard struct{}
+
+func (jsonPathWildcard) selectNodes(node jsonPathNode, _ interface{}, out []jsonPathNode) []jsonPathNode {
+	return append(out, node.children()...)
+}
+
+type jsonPathIndex int
+
+func (s jsonPathIndex) selectNodes(node jsonPathNode, _ interface{}, out []jsonPathNode) []jsonPathNode {
+	array, ok := node.value.([]interface{})
+	if !ok {
+		return out
+	}
+	idx := int(s)
+	if idx < 0 {
+		idx += len(array)
+	}
+	if idx >= 0 && idx < len(array) {
+		out = append(out, node.element(idx, array[idx]))
+	}
+	return out
+}
+
+type jsonPathSlice struct {
+	start, end *int
+	step       int
+}
+
+func (s jsonPathSlice) selectNodes(node jsonPathNode, _ interface{}, out []jsonPathNode) []jsonPathNode {
+	array, ok := node.value.([]interface{})
+	if !ok || s.step == 0 {
+		return out
+	}
+
+	length := len(array)
+	bound := func(idx *int, defaultValue, low, high int) int {
+		if idx == nil {
+			return defaultValue
+		}
+		value := *idx
+		if value < 0 {
+			value += length
+		}
+		if value < low {
+			return low
+		}
+		if value > high {
+			return high
+		}
+		return value
+	}
+
+	if s.step > 0 {
+		lower, upper := bound(s.start, 0, 0, length), bound(s.end, length, 0, length)
+		for idx := lower; idx < upper; idx += s.step {
+			out = append(out, node.element(idx, array[idx]))
+		}
+		return out
+	}
+	upper, lower := bound(s.start, length-1, -1, length-1), bound(s.end, -1, -1, length-1)
+	for idx := upper; idx > lower; idx += s.step {
+		out = append(out, node.element(idx, array[idx]))
+	}
+	return out
+}
+
+type jsonPathFilter struct {
+	expr jsonPathExpr
+}
+
+func (s jsonPathFilter) selectNodes(node jsonPathNode, root interface{}, out []jsonPathNode) []jsonPathNode {
+	for _, child := range node.children() {
+		if s.expr.test(child.value, root) {
+			out = append(out, child)
+		}
+	}
+	return out
+}
+
+// jsonPathExpr 过滤表达式
+type jsonPathExpr interface {
+	test(current, root interface{}) bool
+}
+
+type jsonPathOr struct{ left, right jsonPathExpr }
+
+func (e jsonPathOr) test(current, root interface{}) bool {
+	return e.left.test(current, root) || e.right.test(current, root)
+}
+
+type jsonPathAnd struct{ left, right jsonPathExpr }
+
+func (e jsonPathAnd) test(current, root interface{}) bool {
+	return e.left.test(current, root) && e.right.test(current, root)
+}
+
+type jsonPathNot struct{ expr jsonPathExpr }
+
+func (e jsonPathNot) test(current, root interface{}) bool {
+	return !e.expr.test(current, root)
+}
+
+// jsonPathExists 路径至少匹配一个节点
+type jsonPathExists struct{ query jsonPathQuery }
+
+func (e jsonPathExists) test(current, root interface{}) bool {
+	return len(e.query.eval(current, root)) > 0
+}
+
+type jsonPathCompare struct {
+	op          string
+	left, right jsonPathOperand
+}
+
+func (e jsonPathCompare) test(current, root interface{}) bool {
+	left, leftOK := e.left.value(current, root)
+	right, rightOK := e.right.value(current, root)
+
+	switch e.op {
+	case "==":
+		return jsonPathEqual(left, leftOK, right, rightOK)
+	case "!=":
+		return !jsonPathEqual(left, leftOK, right, rightOK)
+	case "<":
+		return leftOK && rightOK && jsonPathLess(left, right)
+	case "<=":
+		return leftOK && rightOK && (jsonPathLess(left, right) || jsonValuesEqual(left, right))
+	case ">":
+		return leftOK && rightOK && jsonPathLess(right, left)
+	default: // ">="
+		return leftOK && rightOK && (jsonPathLess(right, left) || jsonValuesEqual(left, right))
+	}
+}
+
+// jsonPathEqual 两边都不存在时视为相等
+func jsonPathEqual(left interface{}, leftOK bool, right interface{}, rightOK bool) bool {
+	if !leftOK || !rightOK {
+		return leftOK == rightOK
+	}
+	return jsonValuesEqual(left, right)
+}
+
+// jsonPathLess 仅数字与数字、字符串与字符串之间可以比较大小
+func jsonPathLess(left, right interface{}) bool {
+	if leftStr, ok := left.(string); ok {
+		rightStr, ok := right.(string)
+		return ok && leftStr < rightStr
+	}
+	leftNumber, ok := jsonNumberValue(left)
+	if !ok {
+		return false
+	}
+	rightNumber, ok := jsonNumberValue(right)
+	return ok && leftNumber.Cmp(rightNumber) < 0
+}
+
+// jsonPathOperand 比较的操作数，值不存在（路径没有恰好匹配一个节点）时 ok 为 false
+type jsonPathOperand interface {
+	value(current, root interface{}) (interface{}, bool)
+}
+
+type jsonPathLiteral struct{ literal interface{} }
+
+func (o jsonPathLiteral) value(_, _ interface{}) (interface{}, bool) {
+	return o.literal, true
+}
+
+// jsonPathQuery 过滤表达式中以 @（当前节点）或 $（根节点）开头的路径
+type jsonPathQuery struct {
+	relative bool
+	segments []jsonPathSegment
+}
+
+func (q jsonPathQuery) eval(current, root interface{}) []jsonPathNode {
+	start := root
+	if q.relative {
+		start = current
+	}
+	return evalJSONPath(q.segments, []jsonPathNode{{path: "$", value: start}}, root)
+}
+
+func (q jsonPathQuery) value(current, root interface{}) (interface{}, bool) {
+	nodes := q.eval(current, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0].value, true
+}
+
+type jsonPathParser struct {
+	src string
+	pos int
+}
+
+func (p *jsonPathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d in %q", ErrInvalidJSONPath, fmt.Sprintf(format, args...), p.pos, p.src)
+}
+
+func (p *jsonPathParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *jsonPathParser) consume(c byte) bool {
+	if p.peek() == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathParser) consumeStr(s string) bool {
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathParser) skipSpaces() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// parseSegments 解析 $ 或 @ 之后的全部段，遇到无法识别的字符时停止（不消耗其前面的空白）
+func (p *jsonPathParser) parseSegments() ([]jsonPathSegment, error) {
+	var segments []jsonPathSegment
+	for {
+		start := p.pos
+		p.skipSpaces()
+
+		var (
+			segment jsonPathSegment
+			err     error
+		)
+		switch {
+		case p.consumeStr(".."):
+			segment.descendant = true
+			if p.peek() == '[' {
+				segment.selectors, err = p.parseBracket()
+			} else {
+				segment.selectors, err = p.parseDotSelector()
+			}
+		case p.consume('.'):
+			segment.selectors, err = p.parseDotSelector()
+		case p.peek() == '[':
+			segment.selectors, err = p.parseBracket()
+		default:
+			p.pos = start
+			return segments, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+}
+
+func (p *jsonPathParser) parseDotSelector() ([]jsonPathSelector, error) {
+	if p.consume('*') {
+		return []jsonPathSelector{jsonPathWildcard{}}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		isNameChar := r == '_' || r >= 0x80 || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !isNameChar && !(p.pos > start && r >= '0' && r <= '9') {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return nil, p.errorf("expected member name")
+	}
+	return []jsonPathSelector{jsonPathName(p.src[start:p.pos])}, nil
+}
+
+func (p *jsonPathParser) parseBracket() ([]jsonPathSelector, error) {
+	p.pos++ // '['
+	var selectors []jsonPathSelector
+	for {
+		p.skipSpaces()
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+
+		p.skipSpaces()
+		if p.consume(']') {
+			return selectors, nil
+		}
+		if !p.consume(',') {
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func (p *jsonPathParser) parseSelector() (jsonPathSelector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathName(name), nil
+	case c == '*':
+		p.pos++
+		return jsonPathWildcard{}, nil
+	case c == '?':
+		p.pos++
+		p.skipSpaces()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathFilter{expr: expr}, nil
+	case c == '-' || c == ':' || (c >= '0' && c <= '9'):
+		return p.parseIndexOrSlice()
+	case p.pos >= len(p.src):
+		return nil, p.errorf("unexpected end")
+	default:
+		return nil, p.errorf("unexpected %q in selector", c)
+	}
+}
+
+func (p *jsonPathParser) parseIndexOrSlice() (jsonPathSelector, error) {
+	var bounds [3]*int
+	for part := 0; part < 3; part++ {
+		p.skipSpaces()
+		if c := p.peek(); c == '-' || (c >= '0' && c <= '9') {
+			value, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			bounds[part] = &value
+		}
+		p.skipSpaces()
+		if part == 0 && p.peek() != ':' {
+			if bounds[0] == nil {
+				return nil, p.errorf("expected index")
+			}
+			return jsonPathIndex(*bounds[0]), nil
+		}
+		if part == 2 || !p.consume(':') {
+			break
+		}
+	}
+
+	slice := jsonPathSlice{start: bounds[0], end: bounds[1], step: 1}
+	if bounds[2] != nil {
+		slice.step = *bounds[2]
+	}
+	return slice, nil
+}
+
+func (p *jsonPathParser) parseInt() (int, error) {
+	start := p.pos
+	p.consume('-')
+	for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	value, err := strconv.Atoi(p.src[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("invalid integer")
+	}
+	return value, nil
+}
+
+// parseString 解析单引号或双引号字符串，转义规则与 JSON 相同，另外支持 \'
+func (p *jsonPathParser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+
+	var sb strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return sb.String(), nil
+		case c == '\\':
+			if p.pos+1 >= len(p.src) {
+				return "", p.errorf("unterminated string")
+			}
+			escaped := p.src[p.pos+1]
+			p.pos += 2
+			switch escaped {
+			case '\'', '"', '\\', '/':
+				sb.WriteByte(escaped)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				r, err := p.parseUnicodeEscape()
+				if err != nil {
+					return "", err
+				}
+				sb.WriteRune(r)
+			default:
+				p.pos -= 2
+				return "", p.errorf("invalid escape")
+			}
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *jsonPathParser) parseUnicodeEscape() (rune, error) {
+	readHex := func() (rune, bool) {
+		if p.pos+4 > len(p.src) {
+			return 0, false
+		}
+		value, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+		if err != nil {
+			return 0, false
+		}
+		p.pos += 4
+		return rune(value), true
+	}
+
+	r, ok := readHex()
+	if !ok {
+		return 0, p.errorf("invalid unicode escape")
+	}
+	if utf16.IsSurrogate(r) && p.consumeStr(`\u`) {
+		low, ok := readHex()
+		if !ok {
+			return 0, p.errorf("invalid unicode escape")
+		}
+		r = utf16.DecodeRune(r, low)
+	}
+	return r, nil
+}
+
+func (p *jsonPathParser) parseOr() (jsonPathExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if !p.consumeStr("||") {
+			return left, nil
+		}
+		p.skipSpaces()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = jsonPathOr{left: left, right: right}
+	}
+}
+
+func (p *jsonPathParser) parseAnd() (jsonPathExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if !p.consumeStr("&&") {
+			return left, nil
+		}
+		p.skipSpaces()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = jsonPathAnd{left: left, right: right}
+	}
+}
+
+func (p *jsonPathParser) parseUnary() (jsonPathExpr, error) {
+	if p.consume('!') {
+		p.skipSpaces()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathNot{expr: expr}, nil
+	}
+	if p.consume('(') {
+		p.skipSpaces()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.consume(')') {
+			return nil, p.errorf("expected ')'")
+		}
+		return expr, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.consumeStr(op) {
+			continue
+		}
+		p.skipSpaces()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathCompare{op: op, left: left, right: right}, nil
+	}
+
+	query, ok := left.(jsonPathQuery)
+	if !ok {
+		return nil, p.errorf("literal must be compared with a value")
+	}
+	return jsonPathExists{query: query}, nil
+}
+
+func (p *jsonPathParser) parseOperand() (jsonPathOperand, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathQuery{relative: c == '@', segments: segments}, nil
+	case c == '\'' || c == '"':
+		str, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathLiteral{literal: str}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for p.pos < len(p.src) && strings.IndexByte("+-.0123456789eE", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+		number := json.Number(p.src[start:p.pos])
+		if _, err := number.Float64(); err != nil {
+			p.pos = start
+			return nil, p.errorf("invalid number")
+		}
+		return jsonPathLiteral{literal: number}, nil
+	case p.consumeStr("true"):
+		return jsonPathLiteral{literal: true}, nil
+	case p.consumeStr("false"):
+		return jsonPathLiteral{literal: false}, nil
+	case p.consumeStr("null"):
+		return jsonPathLiteral{literal: nil}, nil
+	default:
+		return nil, p.errorf("expected operand")
+	}
+}
+
+// quoteJSONPathName 按 RFC 9535 规范化路径的规则为成员名加单引号
+func quoteJSONPathName(name string) string {
+	var sb strings.Builder
+	sb.WriteByte('\'')
+	for _, r := range name {
+		switch r {
+		case '\'':
+			sb.WriteString(`\'`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&sb, `\u%04x`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('\'')
+	return sb.String()
+}
@@ -0,0 +1,184 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type JSONPathTest struct {
+	suite.Suite
+}
+
+func TestJSONPathTest(t *testing.T) {
+	suite.Run(t, new(JSONPathTest))
+}
+
+const _testJSONPathDoc = `{
+	"store": {
+		"book": [
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+			{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+		],
+		"bicycle": {"color": "red", "price": 399}
+	},
+	"items": [
+		{"id": 1, "status": "ok"},
+		{"id": 2, "status": "failed"},
+		{"id": 3, "status": "failed", "retry": true}
+	],
+	"it's": {"a/b": 1}
+}`
+
+func (j *JSONPathTest) queryValues(path string) []interface{} {
+	compiled, err := JSON.CompileJSONPath(path)
+	require.Nil(j.T(), err, path)
+	require.Equal(j.T(), path, compiled.String())
+	values, err := compiled.Values([]byte(_testJSONPathDoc))
+	require.Nil(j.T(), err, path)
+	return values
+}
+
+func (j *JSONPathTest) TestGopherunJSON_Query_case1() {
+	matches, err := JSON.Query([]byte(_testJSONPathDoc), `$.items[?(@.status=='failed')].id`)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []JSONPathMatch{
+		{Path: "$['items'][1]['id']", Pointer: "/items/1/id", Value: json.Number("2")},
+		{Path: "$['items'][2]['id']", Pointer: "/items/2/id", Value: json.Number("3")},
+	}, matches)
+
+	// 规范化路径对特殊字符转义
+	matches, err = JSON.Query([]byte(_testJSONPathDoc), `$["it's"]['a/b']`)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []JSONPathMatch{{Path: `$['it\'s']['a/b']`, Pointer: "/it's/a~1b", Value: json.Number("1")}}, matches)
+
+	matches, err = JSON.Query([]byte(_testJSONPathDoc), `$`)
+	require.Nil(j.T(), err)
+	require.Len(j.T(), matches, 1)
+	require.Equal(j.T(), "$", matches[0].Path)
+	require.Equal(j.T(), "", matches[0].Pointer)
+
+	matches, err = JSON.Query([]byte(_testJSONPathDoc), `$.missing.x`)
+	require.Nil(j.T(), err)
+	require.Empty(j.T(), matches)
+}
+
+func (j *JSONPathTest) TestGopherunJSON_Query_case2() {
+	cases := []struct {
+		path     string
+		expected []interface{}
+	}{
+		{`$.store.book[*].author`, []interface{}{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{`$..author`, []interface{}{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{`$.store.*.color`, []interface{}{"red"}},
+		{`$.store..price`, []interface{}{json.Number("399"), json.Number("8.95"), json.Number("12.99"), json.Number("8.99"), json.Number("22.99")}},
+		{`$..book[2].title`, []interface{}{"Moby Dick"}},
+		{`$..book[-1].title`, []interface{}{"The Lord of the Rings"}},
+		{`$..book[0,1].title`, []interface{}{"Sayings of the Century", "Sword of Honour"}},
+		{`$..book[:2].title`, []interface{}{"Sayings of the Century", "Sword of Honour"}},
+		{`$..book[1:3].title`, []interface{}{"Sword of Honour", "Moby Dick"}},
+		{`$..book[-2:].title`, []interface{}{"Moby Dick", "The Lord of the Rings"}},
+		{`$..book[::2].title`, []interface{}{"Sayings of the Century", "Moby Dick"}},
+		{`$..book[::-1].price`, []interface{}{json.Number("22.99"), json.Number("8.99"), json.Number("12.99"), json.Number("8.95")}},
+		{`$..book[2:0:-1].price`, []interface{}{json.Number("8.99"), json.Number("12.99")}},
+		{`$..book[0:4:0]`, []interface{}{}},
+		{`$..book[?(@.isbn)].title`, []interface{}{"Moby Dick", "The Lord of the Rings"}},
+		{`$..book[?!@.isbn].title`, []interface{}{"Sayings of the Century", "Sword of Honour"}},
+		{`$..book[?(@.price < 10)].title`, []interface{}{"Sayings of the Century", "Moby Dick"}},
+		{`$..book[?(@.price >= 12.99 && @.category == "fiction")].title`, []interface{}{"Sword of Honour", "The Lord of the Rings"}},
+		{`$..book[?(@.price > 20 || @.author == 'Nigel Rees')].price`, []interface{}{json.Number("8.95"), json.Number("22.99")}},
+		{`$..book[?(!(@.price <= 9) && @.price != 22.99)].title`, []interface{}{"Sword of Honour"}},
+		{`$..book[?(@.price < $.store.bicycle.price / 2)]`, nil},
+		{`$..book[?(@.title > 'S')].title`, []interface{}{"Sayings of the Century", "Sword of Honour", "The Lord of the Rings"}},
+		{`$..book[?(@.isbn == null)].title`, []interface{}{}},
+		{`$..book[?(@.missing == @.other)].price`, []interface{}{json.Number("8.95"), json.Number("12.99"), json.Number("8.99"), json.Number("22.99")}},
+		{`$.items[?(@.retry == true)].id`, []interface{}{json.Number("3")}},
+		{`$.items[?(@.id > $.items[0].id)].status`, []interface{}{"failed", "failed"}},
+		{`$.items[?(@.id == 1.0)].status`, []interface{}{"ok"}},
+		{`$.store.bicycle[*]`, []interface{}{"red", json.Number("399")}},
+		{`$.store.bicycle['color','price']`, []interface{}{"red", json.Number("399")}},
+		{`$ .store .bicycle [ 'color' ]`, []interface{}{"red"}},
+		{`$.store.book[0]["title"]`, []interface{}{"Sayings of the Century"}},
+		{`$.store.book.title`, []interface{}{}},
+		{`$.store.bicycle[0]`, []interface{}{}},
+	}
+	for _, c := range cases {
+		if c.expected == nil {
+			// 不支持算术运算
+			_, err := JSON.CompileJSONPath(c.path)
+			require.True(j.T(), errors.Is(err, ErrInvalidJSONPath), c.path)
+			continue
+		}
+		require.Equal(j.T(), c.expected, j.queryValues(c.path), c.path)
+	}
+}
+
+func (j *JSONPathTest) TestGopherunJSON_Query_case3() {
+	// 已解码的树
+	doc := map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": 1.0}}}
+	matches, err := JSON.Query(doc, `$.a[0].b`)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []JSONPathMatch{{Path: "$['a'][0]['b']", Pointer: "/a/0/b", Value: 1.0}}, matches)
+
+	// 匹配结果的 Pointer 可直接用于修改文档
+	matches, err = JSON.Query([]byte(_testJSONPathDoc), `$.items[?(@.status == 'failed')]`)
+	require.Nil(j.T(), err)
+	data := []byte(_testJSONPathDoc)
+	for _, match := range matches {
+		data, err = JSON.SetBytes(data, match.Pointer+"/status", "retrying")
+		require.Nil(j.T(), err)
+	}
+	statuses, err := JSON.Query(data, `$.items[*].status`)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "retrying", statuses[1].Value)
+	require.Equal(j.T(), "retrying", statuses[2].Value)
+
+	_, err = JSON.Query([]byte(`{`), `$`)
+	require.NotNil(j.T(), err)
+}
+
+func (j *JSONPathTest) TestGopherunJSON_CompileJSONPath() {
+	cases := []string{
+		``,
+		`store.book`,
+		`$.`,
+		`$..`,
+		`$.store[`,
+		`$.store['book'`,
+		`$.store['book]`,
+		`$.store[book]`,
+		`$.store['\x']`,
+		`$.store['\u12']`,
+		`$[1:2:3:4]`,
+		`$[-]`,
+		`$[?(@.a == 1]`,
+		`$[?(@.a ==)]`,
+		`$[?(1)]`,
+		`$[?(@.a == 1.2.3)]`,
+		`$[?(@.a = 1)]`,
+		`$.a b`,
+	}
+	for _, c := range cases {
+		_, err := JSON.CompileJSONPath(c)
+		require.True(j.T(), errors.Is(err, ErrInvalidJSONPath), "%s: %v", c, err)
+	}
+
+	_, err := JSON.Query([]byte(`{}`), `$[`)
+	require.True(j.T(), errors.Is(err, ErrInvalidJSONPath))
+}
@@ -0,0 +1,353 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPointer JSON Pointer 格式错误
+	ErrInvalidPointer = errors.New("invalid JSON pointer")
+
+	// ErrPointerNotFound JSON Pointer 指向的值不存在
+	ErrPointerNotFound = errors.New("JSON pointer not found")
+
+	// ErrJSONTypeMismatch JSON 值的类型与期望不符
+	ErrJSONTypeMismatch = errors.New("JSON type mismatch")
+)
+
+// Get 按 JSON Pointer（RFC 6901）读取值。
+// doc 可以是原始 JSON（[]byte 或 json.RawMessage，数字解码为 json.Number），也可以是已解码的
+// map[string]interface{} / []interface{} 树。pointer 为 "" 时返回整个文档。
+func (i GopherunJSON) Get(doc interface{}, pointer string) (interface{}, error) {
+	tree, err := decodeJSONDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	node := tree
+	for depth, token := range tokens {
+		switch value := node.(type) {
+		case map[string]interface{}:
+			child, ok := value[token]
+			if !ok {
+				return nil, pointerError(ErrPointerNotFound, tokens[:depth+1])
+			}
+			node = child
+		case []interface{}:
+			idx, err := parseJSONArrayIndex(token, len(value))
+			if err != nil || idx >= len(value) {
+				return nil, pointerError(ErrPointerNotFound, tokens[:depth+1])
+			}
+			node = value[idx]
+		default:
+			return nil, pointerError(ErrPointerNotFound, tokens[:depth+1])
+		}
+	}
+	return node, nil
+}
+
+// Exists 判断 JSON Pointer 指向的值是否存在（值为 null 也视为存在）
+func (i GopherunJSON) Exists(doc interface{}, pointer string) bool {
+	_, err := i.Get(doc, pointer)
+	return err == nil
+}
+
+// GetString 按 JSON Pointer 读取字符串
+func (i GopherunJSON) GetString(doc interface{}, pointer string) (string, error) {
+	value, err := i.Get(doc, pointer)
+	if err != nil {
+		return "", err
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", typeMismatchError(pointer, "string", value)
+	}
+	return str, nil
+}
+
+// GetBool 按 JSON Pointer 读取布尔值
+func (i GopherunJSON) GetBool(doc interface{}, pointer string) (bool, error) {
+	value, err := i.Get(doc, pointer)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, typeMismatchError(pointer, "boolean", value)
+	}
+	return b, nil
+}
+
+// GetInt64 按 JSON Pointer 读取整数，数值带小数部分或超出 int64 范围时返回错误
+func (i GopherunJSON) GetInt64(doc interface{}, pointer string) (int64, error) {
+	value, err := i.Get(doc, pointer)
+	if err != nil {
+		return 0, err
+	}
+
+	switch number := value.(type) {
+	case json.Number:
+		n, err := number.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q: %s is not an int64", ErrJSONTypeMismatch, pointer, number)
+		}
+		return n, nil
+	case float64:
+		if number != math.Trunc(number) || number < math.MinInt64 || number >= math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %q: %v is not an int64", ErrJSONTypeMismatch, pointer, number)
+		}
+		return int64(number), nil
+	case int:
+		return int64(number), nil
+	case int64:
+		return number, nil
+	default:
+		return 0, typeMismatchError(pointer, "number", value)
+	}
+}
+
+// Set 按 JSON Pointer 写入值并返回新的文档根（pointer 为 "" 时直接返回 value）。
+// 路径中不存在的中间节点会被创建为对象；数组下标可以是已有元素（替换），也可以是 "-" 或数组长度（追加）。
+// doc 中的 map 与 slice 可能被原地修改，调用方应使用返回值。
+func (i GopherunJSON) Set(doc interface{}, pointer string, value interface{}) (interface{}, error) {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return setJSONPointer(doc, tokens, 0, value, false)
+}
+
+// Delete 按 JSON Pointer 删除值并返回新的文档根，值不存在时返回 ErrPointerNotFound。
+// doc 中的 map 与 slice 可能被原地修改，调用方应使用返回值。
+func (i GopherunJSON) Delete(doc interface{}, pointer string) (interface{}, error) {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	return deleteJSONPointer(doc, tokens, 0)
+}
+
+// SetBytes 在原始 JSON 上按 JSON Pointer 写入值，返回新的 JSON
+func (i GopherunJSON) SetBytes(data []byte, pointer string, value interface{}) ([]byte, error) {
+	tree, err := decodeJSONDoc(data)
+	if err != nil {
+		return nil, err
+	}
+	if tree, err = i.Set(tree, pointer, value); err != nil {
+		return nil, err
+	}
+	return i.Encode(tree)
+}
+
+// DeleteBytes 在原始 JSON 上按 JSON Pointer 删除值，返回新的 JSON
+func (i GopherunJSON) DeleteBytes(data []byte, pointer string) ([]byte, error) {
+	tree, err := decodeJSONDoc(data)
+	if err != nil {
+		return nil, err
+	}
+	if tree, err = i.Delete(tree, pointer); err != nil {
+		return nil, err
+	}
+	return i.Encode(tree)
+}
+
+// setJSONPointer 递归写入，insert 为 true 时在数组中插入而非替换（JSON Patch 的 add 语义）
+func setJSONPointer(node interface{}, tokens []string, depth int, value interface{}, insert bool) (interface{}, error) {
+	if depth == len(tokens) {
+		return value, nil
+	}
+	token, last := tokens[depth], depth == len(tokens)-1
+
+	switch container := node.(type) {
+	case nil:
+		// 创建中间对象
+		child, err := setJSONPointer(nil, tokens, depth+1, value, insert)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{token: child}, nil
+	case map[string]interface{}:
+		child, err := setJSONPointer(container[token], tokens, depth+1, value, insert)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = child
+		return container, nil
+	case []interface{}:
+		idx, err := parseJSONArrayIndex(token, len(container))
+		if err != nil {
+			return nil, pointerError(err, tokens[:depth+1])
+		}
+		if idx > len(container) || (!last && idx == len(container)) {
+			return nil, pointerError(ErrPointerNotFound, tokens[:depth+1])
+		}
+		if last && (insert || idx == len(container)) {
+			container = append(container, nil)
+			copy(container[idx+1:], container[idx:])
+			container[idx] = value
+			return container, nil
+		}
+		child, err := setJSONPointer(container[idx], tokens, depth+1, value, insert)
+		if err != nil {
+			return nil, err
+		}
+		container[idx] = child
+		return container, nil
+	default:
+		return nil, fmt.Errorf("%w: %q: cannot set member of %s", ErrJSONTypeMismatch, buildJSONPointer(tokens[:depth]), jsonValueKind(node))
+	}
+}
+
+func deleteJSONPointer(node interface{}, tokens []string, depth int) (interface{}, error) {
+	token, last := tokens[depth], depth == len(tokens)-1
+
+	switch container := node.(type) {
+	case map[string]interface{}:
+		child, ok := container[token]
+		if !ok {
+			return nil, pointerError(ErrPointerNotFound, tokens[:depth+1])
+		}
+		if last {
+			delete(container, token)
+			return container, nil
+		}
+		child, err := deleteJSONPointer(child, tokens, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = child
+		return container, nil
+	case []interface{}:
+		idx, err := parseJSONArrayIndex(token, len(container))
+		if err != nil || idx >= len(container) {
+			return nil, pointerError(ErrPointerNotFound, tokens[:depth+1])
+		}
+		if last {
+			return append(container[:idx], container[idx+1:]...), nil
+		}
+		child, err := deleteJSONPointer(container[idx], tokens, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		container[idx] = child
+		return container, nil
+	default:
+		return nil, pointerError(ErrPointerNotFound, tokens[:depth+1])
+	}
+}
+
+// decodeJSONDoc 原始 JSON 解码为树（数字使用 json.Number），其余类型原样返回；文档不合法（包括存在多余数据）时返回语法错误
+func decodeJSONDoc(doc interface{}) (interface{}, error) {
+	var data []byte
+	switch raw := doc.(type) {
+	case []byte:
+		data = raw
+	case json.RawMessage:
+		data = raw
+	default:
+		return doc, nil
+	}
+
+	// 与 json.Unmarshal 一样拒绝第一个值之后的多余数据
+	var tree interface{}
+	if err := unmarshalJSONUseNumber(data, &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// parseJSONPointer 将 JSON Pointer 拆分为反转义后的片段
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: %q must start with '/'", ErrInvalidPointer, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for idx, token := range tokens {
+		if strings.Contains(strings.NewReplacer("~0", "", "~1", "").Replace(token), "~") {
+			return nil, fmt.Errorf("%w: %q has invalid escape in %q", ErrInvalidPointer, pointer, token)
+		}
+		tokens[idx] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// buildJSONPointer 由片段构造 JSON Pointer
+func buildJSONPointer(tokens []string) string {
+	var builder strings.Builder
+	for _, token := range tokens {
+		builder.WriteByte('/')
+		builder.WriteString(escapeJSONPointerToken(token))
+	}
+	return builder.String()
+}
+
+// parseJSONArrayIndex 解析数组下标，"-" 表示数组末尾之后的位置，不允许前导零与负数
+func parseJSONArrayIndex(token string, length int) (int, error) {
+	if token == "-" {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: %q is not an array index", ErrInvalidPointer, token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not an array index", ErrInvalidPointer, token)
+	}
+	return idx, nil
+}
+
+func pointerError(err error, tokens []string) error {
+	return fmt.Errorf("%w: %q", err, buildJSONPointer(tokens))
+}
+
+func typeMismatchError(pointer, expected string, value interface{}) error {
+	return fmt.Errorf("%w: %q: expected %s, got %s", ErrJSONTypeMismatch, pointer, expected, jsonValueKind(value))
+}
+
+// jsonValueKind 返回已解码值对应的 JSON 类型名称
+func jsonValueKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number, float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type JSONPointerTest struct {
+	suite.Suite
+}
+
+func TestJSONPointerTest(t *testing.T) {
+	suite.Run(t, new(JSONPointerTest))
+}
+
+const _pointerDoc = `{
+	"foo": ["bar", "baz"],
+	"": 0,
+	"a/b": 1,
+	"m~n": 8,
+	"user": {"id": 9007199254740993, "name": "zhangsan", "active": true, "score": 1.5, "nick": null}
+}`
+
+func (j *JSONPointerTest) TestGopherunJSON_Get_case1() {
+	doc := []byte(_pointerDoc)
+
+	// RFC 6901 示例
+	value, err := JSON.Get(doc, "/foo/0")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "bar", value)
+
+	value, err = JSON.Get(doc, "/")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), json.Number("0"), value)
+
+	value, err = JSON.Get(doc, "/a~1b")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), json.Number("1"), value)
+
+	value, err = JSON.Get(doc, "/m~0n")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), json.Number("8"), value)
+
+	value, err = JSON.Get(json.RawMessage(`[1,2]`), "")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []interface{}{json.Number("1"), json.Number("2")}, value)
+}
+
+func (j *JSONPointerTest) TestGopherunJSON_Get_case2() {
+	doc := []byte(_pointerDoc)
+
+	for _, pointer := range []string{"/missing", "/foo/2", "/foo/-", "/foo/01", "/foo/x", "/user/name/x"} {
+		_, err := JSON.Get(doc, pointer)
+		require.True(j.T(), errors.Is(err, ErrPointerNotFound), pointer)
+		require.False(j.T(), JSON.Exists(doc, pointer), pointer)
+	}
+
+	_, err := JSON.Get(doc, "foo")
+	require.True(j.T(), errors.Is(err, ErrInvalidPointer))
+	_, err = JSON.Get(doc, "/m~2n")
+	require.True(j.T(), errors.Is(err, ErrInvalidPointer))
+	_, err = JSON.Get([]byte(`{`), "/a")
+	require.NotNil(j.T(), err)
+
+	require.True(j.T(), JSON.Exists(doc, "/user/nick"))
+}
+
+func (j *JSONPointerTest) TestGopherunJSON_Get_trailingData() {
+	// 第一个值之后的多余数据使整个文档不合法，依赖同一解析的功能均返回语法错误
+	var syntaxErr *json.SyntaxError
+	for _, doc := range []string{`{"a":1} trailing garbage`, `{"a":1} {}`, `{"a":1}]`, ``} {
+		_, err := JSON.Get([]byte(doc), "/a")
+		require.True(j.T(), errors.As(err, &syntaxErr), "%q: %v", doc, err)
+		require.False(j.T(), JSON.Exists([]byte(doc), "/a"), doc)
+	}
+
+	_, err := JSON.Equal([]byte(`{"a":1} xx`), []byte(`{"a":1.0}`))
+	require.True(j.T(), errors.As(err, &syntaxErr))
+	_, err = JSON.Diff([]byte(`{"a":1}`), []byte(`{"a":1} xx`))
+	require.True(j.T(), errors.As(err, &syntaxErr))
+	_, err = JSON.ApplyPatch([]byte(`{"a":1} xx`), []byte(`[]`))
+	require.True(j.T(), errors.As(err, &syntaxErr))
+	_, err = JSON.CreatePatch([]byte(`{}`), []byte(`{} {}`))
+	require.True(j.T(), errors.As(err, &syntaxErr))
+	_, err = JSON.SetBytes([]byte(`{} x`), "/a", 1)
+	require.True(j.T(), errors.As(err, &syntaxErr))
+	_, err = JSON.CompileSchema([]byte(`{"type":"object"} x`))
+	require.True(j.T(), errors.Is(err, ErrInvalidSchema))
+	_, err = JSON.Query([]byte(`{"a":1} x`), "$.a")
+	require.True(j.T(), errors.As(err, &syntaxErr))
+	_, err = JSON.Flatten([]byte(`{"a":1} x`), JSONFlattenOptions{})
+	require.True(j.T(), errors.As(err, &syntaxErr))
+}
+
+func (j *JSONPointerTest) TestGopherunJSON_TypedGetters() {
+	doc := []byte(_pointerDoc)
+
+	name, err := JSON.GetString(doc, "/user/name")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "zhangsan", name)
+
+	active, err := JSON.GetBool(doc, "/user/active")
+	require.Nil(j.T(), err)
+	require.True(j.T(), active)
+
+	// 原始 JSON 中的大整数不会丢失精度
+	id, err := JSON.GetInt64(doc, "/user/id")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), int64(9007199254740993), id)
+
+	_, err = JSON.GetInt64(doc, "/user/score")
+	require.True(j.T(), errors.Is(err, ErrJSONTypeMismatch))
+
+	_, err = JSON.GetString(doc, "/user/active")
+	require.True(j.T(), errors.Is(err, ErrJSONTypeMismatch))
+	require.Contains(j.T(), err.Error(), "expected string, got boolean")
+
+	_, err = JSON.GetBool(doc, "/user/nick")
+	require.True(j.T(), errors.Is(err, ErrJSONTypeMismatch))
+
+	_, err = JSON.GetInt64(doc, "/user/name")
+	require.True(j.T(), errors.Is(err, ErrJSONTypeMismatch))
+
+	_, err = JSON.GetString(doc, "/missing")
+	require.True(j.T(), errors.Is(err, ErrPointerNotFound))
+
+	// 已解码的树
+	var tree map[string]interface{}
+	require.Nil(j.T(), JSON.DecodeByJSONStr(`{"n":12,"f":1.5,"big":1e20}`, &tree))
+	n, err := JSON.GetInt64(tree, "/n")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), int64(12), n)
+	_, err = JSON.GetInt64(tree, "/f")
+	require.True(j.T(), errors.Is(err, ErrJSONTypeMismatch))
+	_, err = JSON.GetInt64(tree, "/big")
+	require.True(j.T(), errors.Is(err, ErrJSONTypeMismatch))
+	n, err = JSON.GetInt64(map[string]interface{}{"i": 3}, "/i")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), int64(3), n)
+}
+
+func (j *JSONPointerTest) TestGopherunJSON_Set() {
+	doc := map[string]interface{}{"list": []interface{}{"a"}}
+
+	// 创建中间对象
+	root, err := JSON.Set(doc, "/a/b/c", 1)
+	require.Nil(j.T(), err)
+
+	// 数组替换与追加
+	root, err = JSON.Set(root, "/list/0", "x")
+	require.Nil(j.T(), err)
+	root, err = JSON.Set(root, "/list/-", "y")
+	require.Nil(j.T(), err)
+	root, err = JSON.Set(root, "/list/2", "z")
+	require.Nil(j.T(), err)
+
+	require.Equal(j.T(), map[string]interface{}{
+		"a":    map[string]interface{}{"b": map[string]interface{}{"c": 1}},
+		"list": []interface{}{"x", "y", "z"},
+	}, root)
+
+	_, err = JSON.Set(root, "/list/5", "z")
+	require.True(j.T(), errors.Is(err, ErrPointerNotFound))
+	_, err = JSON.Set(root, "/list/-/a", "z")
+	require.True(j.T(), errors.Is(err, ErrPointerNotFound))
+	_, err = JSON.Set(root, "/list/x", "z")
+	require.True(j.T(), errors.Is(err, ErrInvalidPointer))
+	_, err = JSON.Set(root, "/a/b/c/d", "z")
+	require.True(j.T(), errors.Is(err, ErrJSONTypeMismatch))
+	_, err = JSON.Set(root, "a", "z")
+	require.True(j.T(), errors.Is(err, ErrInvalidPointer))
+
+	// 替换根节点
+	root, err = JSON.Set(root, "", "root")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "root", root)
+}
+
+func (j *JSONPointerTest) TestGopherunJSON_Delete() {
+	var doc interface{}
+	require.Nil(j.T(), JSON.DecodeByJSONStr(`{"a":{"b":[1,2,3]},"c":1}`, &doc))
+
+	doc, err := JSON.Delete(doc, "/a/b/1")
+	require.Nil(j.T(), err)
+	doc, err = JSON.Delete(doc, "/c")
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{1.0, 3.0}}}, doc)
+
+	_, err = JSON.Delete(doc, "/c")
+	require.True(j.T(), errors.Is(err, ErrPointerNotFound))
+	_, err = JSON.Delete(doc, "/a/b/5")
+	require.True(j.T(), errors.Is(err, ErrPointerNotFound))
+	_, err = JSON.Delete(doc, "/a/b/0/x")
+	require.True(j.T(), errors.Is(err, ErrPointerNotFound))
+	_, err = JSON.Delete(doc, "/x/y")
+	require.True(j.T(), errors.Is(err, ErrPointerNotFound))
+	_, err = JSON.Delete(doc, "x")
+	require.True(j.T(), errors.Is(err, ErrInvalidPointer))
+
+	doc, err = JSON.Delete(doc, "")
+	require.Nil(j.T(), err)
+	require.Nil(j.T(), doc)
+}
+
+func (j *JSONPointerTest) TestGopherunJSON_SetBytes_DeleteBytes() {
+	data, err := JSON.SetBytes([]byte(`{"id":9007199254740993,"tags":["a"]}`), "/meta/owner", "zhangsan")
+	require.Nil(j.T(), err)
+	require.JSONEq(j.T(), `{"id":9007199254740993,"tags":["a"],"meta":{"owner":"zhangsan"}}`, string(data))
+	require.Contains(j.T(), string(data), "9007199254740993")
+
+	data, err = JSON.DeleteBytes(data, "/tags/0")
+	require.Nil(j.T(), err)
+	require.JSONEq(j.T(), `{"id":9007199254740993,"tags":[],"meta":{"owner":"zhangsan"}}`, string(data))
+
+	_, err = JSON.SetBytes([]byte(`{`), "/a", 1)
+	require.NotNil(j.T(), err)
+	_, err = JSON.SetBytes([]byte(`[]`), "/a", 1)
+	require.NotNil(j.T(), err)
+	_, err = JSON.DeleteBytes([]byte(`{`), "/a")
+	require.NotNil(j.T(), err)
+	_, err = JSON.DeleteBytes([]byte(`{}`), "/a")
+	require.True(j.T(), errors.Is(err, ErrPointerNotFound))
+}
@@ -0,0 +1,318 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONMaskStyle 掩码方式
+type JSONMaskStyle string
+
+const (
+	JSONMaskFull  JSONMaskStyle = "full"  // 整体替换为 "******"，不泄露原值长度
+	JSONMaskLast4 JSONMaskStyle = "last4" // 字符串与数字保留末尾 4 个字符，如 "******1234"；不足 8 个字符时整体替换
+	JSONMaskHash  JSONMaskStyle = "hash"  // 替换为以 HashKey 为密钥的 HMAC-SHA256 的前 16 位，如 "hmac:5e884898da280471"，相同的值得到相同的结果
+)
+
+const _jsonMaskText = "******"
+
+// ErrInvalidMaskStyle 不支持的掩码方式
+var ErrInvalidMaskStyle = errors.New("invalid JSON mask style")
+
+// DefaultJSONRedactKeys 默认按键名脱敏的关键字
+var DefaultJSONRedactKeys = []string{"password", "passwd", "secret", "token", "apikey", "api_key", "authorization", "credential"}
+
+// JSONRedactOptions 脱敏配置
+type JSONRedactOptions struct {
+	// Style 默认的掩码方式，零值为 JSONMaskFull
+	Style JSONMaskStyle
+
+	// Keys 键名关键字，键名包含其中任意一个（不区分大小写）时脱敏，如 "token" 匹配 "accessToken"；
+	// 为 nil 时使用 DefaultJSONRedactKeys，不需要按键名脱敏时传入空切片
+	Keys []string
+
+	// Pointers 需要脱敏的 JSON Pointer，片段 "*" 匹配任意键或下标，如 "/users/*/phone"
+	Pointers []string
+
+	// HashKey JSONMaskHash 使用的 HMAC 密钥，Style 为 JSONMaskHash 时必须设置。
+	// 掩码结果只用于在日志中关联相同的值；密钥应随机生成并妥善保管，泄露后密码、PIN、卡号等低熵的值可以被暴力还原。
+	// 未设置时 `mask:"hash"` 标记的字段按 JSONMaskFull 处理
+	HashKey []byte
+}
+
+// Redact 将 obj 编码为脱敏后的 JSON，用于日志输出。以下值会被掩码：
+// 带有 mask 标签的结构体字段（`mask:"true"` 使用 opts.Style，也可以写明方式，如 `mask:"last4"`）、
+// 键名匹配 opts.Keys 的字段，以及 opts.Pointers 指向的值。
+// obj 可以是原始 JSON（[]byte 或 json.RawMessage），此时只按键名与 JSON Pointer 脱敏。
+// 掩码后的值均为字符串，null 保持不变。
+func (i GopherunJSON) Redact(obj interface{}, opts JSONRedactOptions) ([]byte, error) {
+	redactor, err := newJSONRedactor(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		data  []byte
+		value reflect.Value
+	)
+	switch raw := obj.(type) {
+	case []byte:
+		data = raw
+	case json.RawMessage:
+		data = raw
+	default:
+		if data, err = i.EncodeWithOptions(obj, JSONEncodeOptions{}); err != nil {
+			return nil, err
+		}
+		value = reflect.ValueOf(obj)
+	}
+
+	tree, err := decodeOrderedJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	tree = redactor.redact(tree, value, nil, "")
+
+	var buf bytes.Buffer
+	writeOrderedJSON(&buf, tree, false)
+	return buf.Bytes(), nil
+}
+
+// RedactToJSONStr 与 Redact 相同，返回 JSON 字符串
+func (i GopherunJSON) RedactToJSONStr(obj interface{}, opts JSONRedactOptions) (jsonStr string, err error) {
+	bytes, err := i.Redact(obj, opts)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+type jsonRedactor struct {
+	style      JSONMaskStyle
+	hashKey    []byte
+	keys       []string
+	pointers   [][]string
+	fieldCache map[reflect.Type]map[string]jsonField
+}
+
+func newJSONRedactor(opts JSONRedactOptions) (*jsonRedactor, error) {
+	r := &jsonRedactor{style: opts.Style, hashKey: opts.HashKey, fieldCache: map[reflect.Type]map[string]jsonField{}}
+	if r.style == "" {
+		r.style = JSONMaskFull
+	}
+	if err := checkJSONMaskStyle(r.style); err != nil {
+		return nil, err
+	}
+	if r.style == JSONMaskHash && len(r.hashKey) == 0 {
+		return nil, fmt.Errorf("%w: %q requires HashKey", ErrInvalidMaskStyle, r.style)
+	}
+
+	keys := opts.Keys
+	if keys == nil {
+		keys = DefaultJSONRedactKeys
+	}
+	for _, key := range keys {
+		if key != "" {
+			r.keys = append(r.keys, strings.ToLower(key))
+		}
+	}
+	for _, pointer := range opts.Pointers {
+		tokens, err := parseJSONPointer(pointer)
+		if err != nil {
+			return nil, err
+		}
+		r.pointers = append(r.pointers, tokens)
+	}
+	return r, nil
+}
+
+func checkJSONMaskStyle(style JSONMaskStyle) error {
+	switch style {
+	case JSONMaskFull, JSONMaskLast4, JSONMaskHash:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidMaskStyle, style)
+	}
+}
+
+// redact 递归处理树节点，v 为节点对应的 Go 值（原始 JSON 时无效），tokens 为节点的 JSON Pointer 片段，
+// style 非空表示节点已被标记为需要掩码
+func (r *jsonRedactor) redact(node interface{}, v reflect.Value, tokens []string, style JSONMaskStyle) interface{} {
+	if style == "" && r.matchPointer(tokens) {
+		style = r.style
+	}
+	if style != "" {
+		return r.mask(node, style)
+	}
+
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			v = reflect.Value{}
+			break
+		}
+		v = v.Elem()
+	}
+	if v.IsValid() && (v.Type().Implements(_jsonMarshalerType) || reflect.PtrTo(v.Type()).Implements(_jsonMarshalerType) ||
+		v.Type().Implements(_textMarshalerType) || reflect.PtrTo(v.Type()).Implements(_textMarshalerType)) {
+		// 自定义编码的类型无法与编码结果对应，仅按键名与 JSON Pointer 处理
+		v = reflect.Value{}
+	}
+
+	switch value := node.(type) {
+	case *orderedJSONObject:
+		for idx, key := range value.keys {
+			var (
+				child      reflect.Value
+				childStyle JSONMaskStyle
+				tagged     bool
+			)
+			if v.IsValid() {
+				child, childStyle, tagged = r.objectChild(v, key)
+			}
+			if !tagged && r.matchKey(key) {
+				childStyle = r.style
+			}
+			value.values[idx] = r.redact(value.values[idx], child, append(tokens, key), childStyle)
+		}
+	case []interface{}:
+		for idx := range value {
+			var child reflect.Value
+			if v.IsValid() && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && idx < v.Len() {
+				child = v.Index(idx)
+			}
+			value[idx] = r.redact(value[idx], child, append(tokens, strconv.Itoa(idx)), "")
+		}
+	}
+	return node
+}
+
+// objectChild 返回对象中 key 对应的 Go 值，以及结构体字段 mask 标签指定的掩码方式；
+// tagged 表示字段带有 mask 标签，`mask:"false"` 可以让字段不按键名脱敏
+func (r *jsonRedactor) objectChild(v reflect.Value, key string) (child reflect.Value, style JSONMaskStyle, tagged bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		fields, ok := r.fieldCache[v.Type()]
+		if !ok {
+			fields = map[string]jsonField{}
+			for _, field := range jsonStructFields(v.Type()) {
+				fields[field.name] = field
+			}
+			r.fieldCache[v.Type()] = fields
+		}
+		field, ok := fields[key]
+		if !ok {
+			return reflect.Value{}, "", false
+		}
+		tag, hasTag := field.tag.Lookup("mask")
+		switch {
+		case !hasTag, tag == "false", tag == "-":
+		case tag == "" || tag == "true":
+			style = r.style
+		default:
+			// 无法识别的方式按 full 处理，宁可多遮盖
+			style = JSONMaskStyle(tag)
+			if checkJSONMaskStyle(style) != nil {
+				style = JSONMaskFull
+			}
+		}
+		fieldValue, err := v.FieldByIndexErr(field.index)
+		if err != nil {
+			return reflect.Value{}, style, hasTag
+		}
+		return fieldValue, style, hasTag
+	case reflect.Map:
+		mapKey, ok := jsonMapKey(v.Type().Key(), key)
+		if !ok {
+			return reflect.Value{}, "", false
+		}
+		return v.MapIndex(mapKey), "", false
+	default:
+		return reflect.Value{}, "", false
+	}
+}
+
+func (r *jsonRedactor) matchKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range r.keys {
+		if strings.Contains(key, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *jsonRedactor) matchPointer(tokens []string) bool {
+	for _, pointer := range r.pointers {
+		if len(pointer) != len(tokens) {
+			continue
+		}
+		matched := true
+		for idx, token := range pointer {
+			if token != "*" && token != tokens[idx] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// mask 按 style 掩码节点，字符串以外的值按其 JSON 文本处理
+func (r *jsonRedactor) mask(node interface{}, style JSONMaskStyle) interface{} {
+	var text string
+	switch value := node.(type) {
+	case nil:
+		return nil
+	case string:
+		text = value
+	default:
+		var buf bytes.Buffer
+		writeOrderedJSON(&buf, node, false)
+		text = buf.String()
+	}
+
+	switch style {
+	case JSONMaskLast4:
+		// 只对字符串与数字保留末尾字符，对象、数组与布尔值整体替换
+		runes := []rune(text)
+		switch node.(type) {
+		case string, json.Number:
+			if len(runes) >= 8 {
+				return _jsonMaskText + string(runes[len(runes)-4:])
+			}
+		}
+		return _jsonMaskText
+	case JSONMaskHash:
+		if len(r.hashKey) == 0 {
+			return _jsonMaskText
+		}
+		mac := hmac.New(sha256.New, r.hashKey)
+		mac.Write([]byte(text))
+		return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:8])
+	default:
+		return _jsonMaskText
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type JSONRedactTest struct {
+	suite.Suite
+}
+
+func TestJSONRedactTest(t *testing.T) {
+	suite.Run(t, new(JSONRedactTest))
+}
+
+type redactTestCard struct {
+	Number string `json:"number" mask:"last4"`
+	Holder string `json:"holder"`
+}
+
+type redactTestUser struct {
+	Name       string            `json:"name"`
+	Password   string            `json:"password"`
+	Phone      string            `json:"phone" mask:"true"`
+	TokenCount int               `json:"tokenCount" mask:"false"`
+	Card       *redactTestCard   `json:"card"`
+	Cards      []redactTestCard  `json:"cards"`
+	Labels     map[string]string `json:"labels"`
+	Secret     *string           `json:"secret"`
+}
+
+func (j *JSONRedactTest) TestGopherunJSON_Redact_case1() {
+	user := redactTestUser{
+		Name:       "alice",
+		Password:   "p@ssw0rd",
+		Phone:      "13800001234",
+		TokenCount: 3,
+		Card:       &redactTestCard{Number: "6222020200001234", Holder: "alice"},
+		Cards:      []redactTestCard{{Number: "123", Holder: "bob"}},
+		Labels:     map[string]string{"apiKey": "k", "env": "prod"},
+	}
+
+	jsonStr, err := JSON.RedactToJSONStr(user, JSONRedactOptions{})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"name":"alice","password":"******","phone":"******","tokenCount":3,"card":{"number":"******1234","holder":"alice"},"cards":[{"number":"******","holder":"bob"}],"labels":{"apiKey":"******","env":"prod"},"secret":null}`, jsonStr)
+
+	// 指针与 JSON Pointer
+	jsonStr, err = JSON.RedactToJSONStr(&user, JSONRedactOptions{Keys: []string{}, Pointers: []string{"/cards/*/holder", "/labels/env"}})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"name":"alice","password":"p@ssw0rd","phone":"******","tokenCount":3,"card":{"number":"******1234","holder":"alice"},"cards":[{"number":"******","holder":"******"}],"labels":{"apiKey":"k","env":"******"},"secret":null}`, jsonStr)
+
+	// 原值不受影响
+	require.Equal(j.T(), "p@ssw0rd", user.Password)
+}
+
+func (j *JSONRedactTest) TestGopherunJSON_Redact_case2() {
+	data := []byte(`{"user":{"name":"alice","Password":"p@ssw0rd","accessToken":"abcdefgh12345678"},"secrets":{"a":1},"items":[{"id":6222020200001234,"flag":true}],"empty":null}`)
+
+	redacted, err := JSON.Redact(data, JSONRedactOptions{Style: JSONMaskLast4, Pointers: []string{"/items/0/id", "/items/0/flag"}})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"user":{"name":"alice","Password":"******w0rd","accessToken":"******5678"},"secrets":"******","items":[{"id":"******1234","flag":"******"}],"empty":null}`, string(redacted))
+
+	redacted, err = JSON.Redact(json.RawMessage(data), JSONRedactOptions{Style: JSONMaskHash, Keys: []string{"NAME"}, HashKey: []byte("test-key")})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"user":{"name":"hmac:ff7a3cd2cfcd73da","Password":"p@ssw0rd","accessToken":"abcdefgh12345678"},"secrets":{"a":1},"items":[{"id":6222020200001234,"flag":true}],"empty":null}`, string(redacted))
+
+	// 整个文档
+	redacted, err = JSON.Redact(data, JSONRedactOptions{Pointers: []string{""}})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `"******"`, string(redacted))
+}
+
+func (j *JSONRedactTest) TestGopherunJSON_Redact_hash() {
+	type account struct {
+		Owner string `json:"owner" mask:"hash"`
+	}
+
+	// 摘要依赖密钥，不同的密钥得到不同的结果
+	jsonStr, err := JSON.RedactToJSONStr(account{Owner: "bob"}, JSONRedactOptions{HashKey: []byte("test-key")})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"owner":"hmac:1520f16c3e94f006"}`, jsonStr)
+	other, err := JSON.RedactToJSONStr(account{Owner: "bob"}, JSONRedactOptions{HashKey: []byte("other-key")})
+	require.Nil(j.T(), err)
+	require.NotEqual(j.T(), jsonStr, other)
+
+	// 没有密钥时不输出摘要
+	jsonStr, err = JSON.RedactToJSONStr(account{Owner: "bob"}, JSONRedactOptions{})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"owner":"******"}`, jsonStr)
+	_, err = JSON.Redact([]byte(`{}`), JSONRedactOptions{Style: JSONMaskHash})
+	require.True(j.T(), errors.Is(err, ErrInvalidMaskStyle))
+}
+
+func (j *JSONRedactTest) TestGopherunJSON_Redact_case3() {
+	_, err := JSON.Redact([]byte(`{}`), JSONRedactOptions{Style: "stars"})
+	require.True(j.T(), errors.Is(err, ErrInvalidMaskStyle))
+
+	_, err = JSON.Redact([]byte(`{}`), JSONRedactOptions{Pointers: []string{"users"}})
+	require.True(j.T(), errors.Is(err, ErrInvalidPointer))
+
+	_, err = JSON.Redact([]byte(`{`), JSONRedactOptions{})
+	require.NotNil(j.T(), err)
+
+	_, err = JSON.Redact(make(chan int), JSONRedactOptions{})
+	require.NotNil(j.T(), err)
+}
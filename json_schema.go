@@ -0,0 +1,564 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidSchema JSON Schema 文档无效
+var ErrInvalidSchema = errors.New("invalid JSON schema")
+
+// JSONViolationSchema 不满足 JSON Schema 约束
+const JSONViolationSchema JSONViolationKind = "schema"
+
+// JSONSchema 编译后的 JSON Schema，可并发复用。
+// 支持 draft 2020-12 的常用子集：type、properties、additionalProperties、required、enum、const、
+// minimum、maximum、exclusiveMinimum、exclusiveMaximum、minLength、maxLength、pattern、
+// items、prefixItems、minItems、maxItems、allOf、anyOf、oneOf、not，以及文档内的 $ref（如 "#/$defs/name"）。
+// 其他关键字（如 title、description、format）会被忽略。
+type JSONSchema struct {
+	root *schemaNode
+}
+
+// schemaNode 编译后的单个子 schema
+type schemaNode struct {
+	location string // 在 schema 文档中的位置，用于错误信息
+
+	// boolean schema：true 接受任何值，false 拒绝任何值
+	always *bool
+
+	ref     string
+	refNode *schemaNode
+
+	types []string
+
+	properties    map[string]*schemaNode
+	additional    *schemaNode
+	hasAdditional bool
+	required      []string
+
+	enum     []interface{}
+	hasEnum  bool
+	constVal interface{}
+	hasConst bool
+
+	minimum, maximum, exclusiveMinimum, exclusiveMaximum *big.Float
+
+	minLength, maxLength *int
+	pattern              *regexp.Regexp
+
+	prefixItems        []*schemaNode
+	items              *schemaNode
+	minItems, maxItems *int
+
+	allOf, anyOf, oneOf []*schemaNode
+	not                 *schemaNode
+}
+
+// CompileSchema 编译 JSON Schema，编译结果可用于多次校验
+func (i GopherunJSON) CompileSchema(schema []byte) (*JSONSchema, error) {
+	doc, err := decodeJSONDoc(schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	compiler := &schemaCompiler{doc: doc, cache: map[string]*schemaNode{}}
+	root, err := compiler.compile(doc, "")
+	if err != nil {
+		return nil, err
+	}
+	if err = compiler.resolveRefs(); err != nil {
+		return nil, err
+	}
+	return &JSONSchema{root: root}, nil
+}
+
+// ValidateSchema 编译 schema 并校验 data，适用于只校验一次的场景
+func (i GopherunJSON) ValidateSchema(schema, data []byte) error {
+	compiled, err := i.CompileSchema(schema)
+	if err != nil {
+		return err
+	}
+	return compiled.Validate(data)
+}
+
+// Validate 校验原始 JSON，不满足约束时返回 *JSONValidationError，其中 Path 为实例中的位置
+func (s *JSONSchema) Validate(data []byte) error {
+	instance, err := decodeJSONDoc(data)
+	if err != nil {
+		return err
+	}
+	return s.validateTree(instance)
+}
+
+// ValidateValue 校验任意可编码的 Go 值（先编码为 JSON 再校验）
+func (s *JSONSchema) ValidateValue(value interface{}) error {
+	instance, err := normalizeJSONValue(value)
+	if err != nil {
+		return err
+	}
+	return s.validateTree(instance)
+}
+
+func (s *JSONSchema) validateTree(instance interface{}) error {
+	violations := s.root.validate(instance, "", nil)
+	if len(violations) > 0 {
+		return &JSONValidationError{Violations: violations}
+	}
+	return nil
+}
+
+type schemaCompiler struct {
+	doc   interface{}
+	cache map[string]*schemaNode
+	refs  []*schemaNode
+}
+
+func (c *schemaCompiler) compile(raw interface{}, location string) (*schemaNode, error) {
+	if node, ok := c.cache[location]; ok {
+		return node, nil
+	}
+	node := &schemaNode{location: location}
+	c.cache[location] = node
+
+	if always, ok := raw.(bool); ok {
+		node.always = &always
+		return node, nil
+	}
+	object, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, c.invalid(location, "schema must be an object or boolean, got %s", jsonValueKind(raw))
+	}
+
+	var err error
+	if ref, ok := object["$ref"]; ok {
+		if node.ref, ok = ref.(string); !ok {
+			return nil, c.invalid(location+"/$ref", "must be a string")
+		}
+		c.refs = append(c.refs, node)
+	}
+
+	if node.types, err = c.compileTypes(object["type"], location+"/type"); err != nil {
+		return nil, err
+	}
+
+	if properties, ok := object["properties"]; ok {
+		propertiesObject, ok := properties.(map[string]interface{})
+		if !ok {
+			return nil, c.invalid(location+"/properties", "must be an object")
+		}
+		node.properties = map[string]*schemaNode{}
+		for name, sub := range propertiesObject {
+			if node.properties[name], err = c.compile(sub, location+"/properties/"+escapeJSONPointerToken(name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if additional, ok := object["additionalProperties"]; ok {
+		node.hasAdditional = true
+		if node.additional, err = c.compile(additional, location+"/additionalProperties"); err != nil {
+			return nil, err
+		}
+	}
+	if required, ok := object["required"]; ok {
+		if node.required, err = c.compileStrings(required, location+"/required"); err != nil {
+			return nil, err
+		}
+	}
+
+	if enum, ok := object["enum"]; ok {
+		if node.enum, ok = enum.([]interface{}); !ok {
+			return nil, c.invalid(location+"/enum", "must be an array")
+		}
+		node.hasEnum = true
+	}
+	node.constVal, node.hasConst = object["const"]
+
+	for keyword, target := range map[string]**big.Float{
+		"minimum":          &node.minimum,
+		"maximum":          &node.maximum,
+		"exclusiveMinimum": &node.exclusiveMinimum,
+		"exclusiveMaximum": &node.exclusiveMaximum,
+	} {
+		if value, ok := object[keyword]; ok {
+			number, ok := jsonNumberValue(value)
+			if !ok {
+				return nil, c.invalid(location+"/"+keyword, "must be a number")
+			}
+			*target = number
+		}
+	}
+	for keyword, target := range map[string]**int{
+		"minLength": &node.minLength,
+		"maxLength": &node.maxLength,
+		"minItems":  &node.minItems,
+		"maxItems":  &node.maxItems,
+	} {
+		if value, ok := object[keyword]; ok {
+			if *target, err = c.compileCount(value, location+"/"+keyword); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if pattern, ok := object["pattern"]; ok {
+		patternStr, ok := pattern.(string)
+		if !ok {
+			return nil, c.invalid(location+"/pattern", "must be a string")
+		}
+		if node.pattern, err = regexp.Compile(patternStr); err != nil {
+			return nil, c.invalid(location+"/pattern", "%v", err)
+		}
+	}
+
+	if items, ok := object["items"]; ok {
+		if node.items, err = c.compile(items, location+"/items"); err != nil {
+			return nil, err
+		}
+	}
+	if node.prefixItems, err = c.compileList(object["prefixItems"], location+"/prefixItems"); err != nil {
+		return nil, err
+	}
+	if node.allOf, err = c.compileList(object["allOf"], location+"/allOf"); err != nil {
+		return nil, err
+	}
+	if node.anyOf, err = c.compileList(object["anyOf"], location+"/anyOf"); err != nil {
+		return nil, err
+	}
+	if node.oneOf, err = c.compileList(object["oneOf"], location+"/oneOf"); err != nil {
+		return nil, err
+	}
+	if not, ok := object["not"]; ok {
+		if node.not, err = c.compile(not, location+"/not"); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// resolveRefs 解析 $ref，仅支持指向同一文档的 "#" 与 "#/..." 形式
+func (c *schemaCompiler) resolveRefs() error {
+	// 解析过程中可能编译出新的含 $ref 的节点，因此按下标遍历
+	for idx := 0; idx < len(c.refs); idx++ {
+		node := c.refs[idx]
+		if !strings.HasPrefix(node.ref, "#") {
+			return c.invalid(node.location+"/$ref", "only local references are supported, got %q", node.ref)
+		}
+		pointer := node.ref[1:]
+		target, err := JSON.Get(c.doc, pointer)
+		if err != nil {
+			return c.invalid(node.location+"/$ref", "cannot resolve %q: %v", node.ref, err)
+		}
+		if node.refNode, err = c.compile(target, pointer); err != nil {
+			return err
+		}
+	}
+	return c.checkRefCycles()
+}
+
+// checkRefCycles 检查不经过 properties、items 等子实例关键字就回到自身的引用环（如 {"$ref":"#"}），
+// 这样的 schema 在校验时会无限递归
+func (c *schemaCompiler) checkRefCycles() error {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[*schemaNode]int{}
+	var visit func(node *schemaNode) *schemaNode
+	visit = func(node *schemaNode) *schemaNode {
+		switch state[node] {
+		case visiting:
+			return node
+		case done:
+			return nil
+		}
+		state[node] = visiting
+		// 以下关键字与当前节点校验同一个实例
+		next := append(append(append([]*schemaNode{node.refNode, node.not}, node.allOf...), node.anyOf...), node.oneOf...)
+		for _, child := range next {
+			if child == nil {
+				continue
+			}
+			if cycle := visit(child); cycle != nil {
+				return cycle
+			}
+		}
+		state[node] = done
+		return nil
+	}
+
+	locations := make([]string, 0, len(c.cache))
+	for location := range c.cache {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+	for _, location := range locations {
+		if cycle := visit(c.cache[location]); cycle != nil {
+			return c.invalid(cycle.location, "reference cycle does not consume any instance")
+		}
+	}
+	return nil
+}
+
+func (c *schemaCompiler) compileTypes(raw interface{}, location string) ([]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var types []string
+	if single, ok := raw.(string); ok {
+		types = []string{single}
+	} else {
+		var err error
+		if types, err = c.compileStrings(raw, location); err != nil {
+			return nil, err
+		}
+	}
+	for _, typ := range types {
+		switch typ {
+		case "null", "boolean", "object", "array", "number", "string", "integer":
+		default:
+			return nil, c.invalid(location, "unknown type %q", typ)
+		}
+	}
+	return types, nil
+}
+
+func (c *schemaCompiler) compileStrings(raw interface{}, location string) ([]string, error) {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, c.invalid(location, "must be an array of strings")
+	}
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		str, ok := item.(string)
+		if !ok {
+			return nil, c.invalid(location, "must be an array of strings")
+		}
+		strs = append(strs, str)
+	}
+	return strs, nil
+}
+
+func (c *schemaCompiler) compileCount(raw interface{}, location string) (*int, error) {
+	number, ok := jsonNumberValue(raw)
+	if !ok || !number.IsInt() || number.Sign() < 0 {
+		return nil, c.invalid(location, "must be a non-negative integer")
+	}
+	count, _ := number.Int64()
+	n := int(count)
+	return &n, nil
+}
+
+func (c *schemaCompiler) compileList(raw interface{}, location string) ([]*schemaNode, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, c.invalid(location, "must be a non-empty array")
+	}
+	nodes := make([]*schemaNode, 0, len(list))
+	for idx, item := range list {
+		node, err := c.compile(item, location+"/"+strconv.Itoa(idx))
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func (c *schemaCompiler) invalid(location, format string, args ...interface{}) error {
+	if location == "" {
+		location = "/"
+	}
+	return fmt.Errorf("%w: %s: %s", ErrInvalidSchema, location, fmt.Sprintf(format, args...))
+}
+
+// validate 校验 instance，将违规项追加到 violations 并返回
+func (n *schemaNode) validate(instance interface{}, path string, violations []JSONViolation) []JSONViolation {
+	fail := func(format string, args ...interface{}) {
+		violations = append(violations, JSONViolation{Path: path, Kind: JSONViolationSchema, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if n.always != nil {
+		if !*n.always {
+			fail("false schema: no value is allowed")
+		}
+		return violations
+	}
+	if n.refNode != nil {
+		violations = n.refNode.validate(instance, path, violations)
+	}
+
+	if len(n.types) > 0 && !schemaTypeMatches(n.types, instance) {
+		fail("type: expected %s, got %s", strings.Join(n.types, " or "), jsonValueKind(instance))
+	}
+	if n.hasConst && !jsonValuesEqual(n.constVal, instance) {
+		fail("const: value must be %s", formatJSONDiffValue(n.constVal))
+	}
+	if n.hasEnum {
+		matched := false
+		for _, allowed := range n.enum {
+			if jsonValuesEqual(allowed, instance) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("enum: value must be one of %s", formatJSONDiffValue(n.enum))
+		}
+	}
+
+	switch value := instance.(type) {
+	case map[string]interface{}:
+		violations = n.validateObject(value, path, violations)
+	case []interface{}:
+		violations = n.validateArray(value, path, violations)
+	case string:
+		length := utf8.RuneCountInString(value)
+		if n.minLength != nil && length < *n.minLength {
+			fail("minLength: length %d is less than %d", length, *n.minLength)
+		}
+		if n.maxLength != nil && length > *n.maxLength {
+			fail("maxLength: length %d is greater than %d", length, *n.maxLength)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(value) {
+			fail("pattern: %q does not match %q", value, n.pattern.String())
+		}
+	default:
+		if number, ok := jsonNumberValue(instance); ok {
+			text := number.Text('g', -1)
+			if n.minimum != nil && number.Cmp(n.minimum) < 0 {
+				fail("minimum: %s is less than %s", text, n.minimum.Text('g', -1))
+			}
+			if n.maximum != nil && number.Cmp(n.maximum) > 0 {
+				fail("maximum: %s is greater than %s", text, n.maximum.Text('g', -1))
+			}
+			if n.exclusiveMinimum != nil && number.Cmp(n.exclusiveMinimum) <= 0 {
+				fail("exclusiveMinimum: %s is not greater than %s", text, n.exclusiveMinimum.Text('g', -1))
+			}
+			if n.exclusiveMaximum != nil && number.Cmp(n.exclusiveMaximum) >= 0 {
+				fail("exclusiveMaximum: %s is not less than %s", text, n.exclusiveMaximum.Text('g', -1))
+			}
+		}
+	}
+
+	for _, sub := range n.allOf {
+		violations = sub.validate(instance, path, violations)
+	}
+	if len(n.anyOf) > 0 {
+		matched := false
+		for _, sub := range n.anyOf {
+			if len(sub.validate(instance, path, nil)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("anyOf: value does not match any schema")
+		}
+	}
+	if len(n.oneOf) > 0 {
+		matched := 0
+		for _, sub := range n.oneOf {
+			if len(sub.validate(instance, path, nil)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			fail("oneOf: value matches %d schemas, expected exactly 1", matched)
+		}
+	}
+	if n.not != nil && len(n.not.validate(instance, path, nil)) == 0 {
+		fail("not: value must not match the schema")
+	}
+	return violations
+}
+
+func (n *schemaNode) validateObject(object map[string]interface{}, path string, violations []JSONViolation) []JSONViolation {
+	for _, name := range n.required {
+		if _, ok := object[name]; !ok {
+			violations = append(violations, JSONViolation{
+				Path:    path + "/" + escapeJSONPointerToken(name),
+				Kind:    JSONViolationSchema,
+				Message: fmt.Sprintf("required: missing property %q", name),
+			})
+		}
+	}
+
+	for _, key := range sortedJSONKeys(object) {
+		childPath := path + "/" + escapeJSONPointerToken(key)
+		if sub, ok := n.properties[key]; ok {
+			violations = sub.validate(object[key], childPath, violations)
+			continue
+		}
+		if !n.hasAdditional {
+			continue
+		}
+		if n.additional.always != nil && !*n.additional.always {
+			violations = append(violations, JSONViolation{
+				Path:    childPath,
+				Kind:    JSONViolationSchema,
+				Message: fmt.Sprintf("additionalProperties: property %q is not allowed", key),
+			})
+			continue
+		}
+		violations = n.additional.validate(object[key], childPath, violations)
+	}
+	return violations
+}
+
+func (n *schemaNode) validateArray(array []interface{}, path string, violations []JSONViolation) []JSONViolation {
+	if n.minItems != nil && len(array) < *n.minItems {
+		violations = append(violations, JSONViolation{Path: path, Kind: JSONViolationSchema, Message: fmt.Sprintf("minItems: %d items is less than %d", len(array), *n.minItems)})
+	}
+	if n.maxItems != nil && len(array) > *n.maxItems {
+		violations = append(violations, JSONViolation{Path: path, Kind: JSONViolationSchema, Message: fmt.Sprintf("maxItems: %d items is greater than %d", len(array), *n.maxItems)})
+	}
+
+	for idx, item := range array {
+		childPath := path + "/" + strconv.Itoa(idx)
+		switch {
+		case idx < len(n.prefixItems):
+			violations = n.prefixItems[idx].validate(item, childPath, violations)
+		case n.items != nil:
+			violations = n.items.validate(item, childPath, violations)
+		}
+	}
+	return violations
+}
+
+// schemaTypeMatches 判断实例是否属于 types 中的任一类型，integer 为没有小数部分的 number
+func schemaTypeMatches(types []string, instance interface{}) bool {
+	kind := jsonValueKind(instance)
+	for _, typ := range types {
+		if typ == kind {
+			return true
+		}
+		if typ == "integer" && kind == "number" {
+			if number, ok := jsonNumberValue(instance); ok && number.IsInt() {
+				return true
+			}
+		}
+	}
+	return false
+}
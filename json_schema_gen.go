@@ -0,0 +1,345 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const _jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	// ErrUnsupportedSchemaType 类型无法用 JSON 表示（如 chan、func、complex）
+	ErrUnsupportedSchemaType = errors.New("unsupported type for JSON schema")
+	// ErrInvalidSchemaTag jsonschema 结构体标签无效
+	ErrInvalidSchemaTag = errors.New("invalid jsonschema tag")
+)
+
+var (
+	_jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	_textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	_timeType          = reflect.TypeOf(time.Time{})
+	_jsonNumberType    = reflect.TypeOf(json.Number(""))
+)
+
+// GenerateSchema 根据 Go 类型生成 draft 2020-12 JSON Schema，描述 Encode 对该类型的输出。
+// obj 可以是该类型的值（如 User{}、(*User)(nil)）或 reflect.Type。字段规则与 encoding/json 一致：
+//   - 属性名取自 json 标签，`json:"-"` 与未导出字段被忽略，匿名嵌入结构体的字段被提升；
+//   - 没有 omitempty 的字段总会被编码，因此列入 required，`required:"true"` 的字段同样列入；
+//   - 没有 omitempty 的指针、切片与 map 字段允许 null（nil 值编码为 null）；
+//   - 具名结构体放入 $defs 并通过 $ref 引用，从而支持递归类型；
+//   - time.Time 为 date-time 格式的字符串，[]byte 为 base64 字符串，实现 json.Marshaler 的类型不做约束。
+//
+// 可通过 jsonschema 标签追加约束，多个约束以逗号分隔，enum 的多个取值以 | 分隔；
+// title、description 与 pattern 的取值为标签的剩余部分（可以包含逗号），需放在最后，例如：
+//
+//	Age  int    `json:"age" jsonschema:"minimum=0,maximum=150"`
+//	Role string `json:"role" jsonschema:"enum=admin|user,description=用户角色，可选 admin, user"`
+//	Code string `json:"code" jsonschema:"maxLength=3,pattern=^[a-z]{1,3}$"`
+//
+// 支持的约束：title、description、format、pattern、enum、minimum、maximum、exclusiveMinimum、
+// exclusiveMaximum、minLength、maxLength、minItems、maxItems。
+func (i GopherunJSON) GenerateSchema(obj interface{}) ([]byte, error) {
+	typ, ok := obj.(reflect.Type)
+	if !ok {
+		typ = reflect.TypeOf(obj)
+	}
+	if typ == nil {
+		return nil, fmt.Errorf("%w: nil", ErrUnsupportedSchemaType)
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	generator := &schemaGenerator{names: map[reflect.Type]string{}, used: map[string]bool{}, defs: &orderedJSONObject{}}
+	var (
+		root *orderedJSONObject
+		err  error
+	)
+	if typ.Kind() == reflect.Struct && !isSchemaSpecialType(typ) {
+		// 根结构体直接内联，递归引用自身时使用 "#"
+		generator.names[typ] = ""
+		root, err = generator.structSchema(typ)
+	} else {
+		root, err = generator.generate(typ)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &orderedJSONObject{}
+	schema.set("$schema", _jsonSchemaDraft)
+	for idx, key := range root.keys {
+		schema.set(key, root.values[idx])
+	}
+	if len(generator.defs.keys) > 0 {
+		schema.set("$defs", generator.defs)
+	}
+
+	var buf bytes.Buffer
+	writeOrderedJSON(&buf, schema, false)
+	return buf.Bytes(), nil
+}
+
+type schemaGenerator struct {
+	names map[reflect.Type]string // 已生成定义的结构体类型 -> $defs 中的名称
+	used  map[string]bool
+	defs  *orderedJSONObject
+}
+
+func (g *schemaGenerator) generate(typ reflect.Type) (*orderedJSONObject, error) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ == _timeType:
+		return schemaObject("type", "string", "format", "date-time"), nil
+	case typ == _jsonNumberType:
+		return schemaObject("type", "number"), nil
+	case typ.Implements(_jsonMarshalerType) || reflect.PtrTo(typ).Implements(_jsonMarshalerType):
+		// 自定义编码的输出结构未知，不做约束
+		return &orderedJSONObject{}, nil
+	case typ.Implements(_textMarshalerType) || reflect.PtrTo(typ).Implements(_textMarshalerType):
+		return schemaObject("type", "string"), nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return schemaObject("type", "boolean"), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return schemaObject("type", "integer"), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return schemaObject("type", "integer", "minimum", json.Number("0")), nil
+	case reflect.Float32, reflect.Float64:
+		return schemaObject("type", "number"), nil
+	case reflect.String:
+		return schemaObject("type", "string"), nil
+	case reflect.Interface:
+		return &orderedJSONObject{}, nil
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return schemaObject("type", "string", "contentEncoding", "base64"), nil
+		}
+		items, err := g.generate(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return schemaObject("type", "array", "items", items), nil
+	case reflect.Array:
+		items, err := g.generate(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		length := json.Number(strconv.Itoa(typ.Len()))
+		return schemaObject("type", "array", "items", items, "minItems", length, "maxItems", length), nil
+	case reflect.Map:
+		keyType := typ.Key()
+		switch keyType.Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !keyType.Implements(_textMarshalerType) {
+				return nil, fmt.Errorf("%w: map key %s", ErrUnsupportedSchemaType, keyType)
+			}
+		}
+		values, err := g.generate(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return schemaObject("type", "object", "additionalProperties", values), nil
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return g.structSchema(typ)
+		}
+		return g.structRef(typ)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSchemaType, typ)
+	}
+}
+
+// structRef 将具名结构体放入 $defs 并返回对它的引用
+func (g *schemaGenerator) structRef(typ reflect.Type) (*orderedJSONObject, error) {
+	name, ok := g.names[typ]
+	if !ok {
+		name = typ.Name()
+		for suffix := 2; g.used[name]; suffix++ {
+			name = typ.Name() + strconv.Itoa(suffix)
+		}
+		g.used[name] = true
+		g.names[typ] = name
+
+		def, err := g.structSchema(typ)
+		if err != nil {
+			return nil, err
+		}
+		g.defs.set(name, def)
+	}
+
+	if name == "" {
+		return schemaObject("$ref", "#"), nil
+	}
+	return schemaObject("$ref", "#/$defs/"+escapeJSONPointerToken(name)), nil
+}
+
+func (g *schemaGenerator) structSchema(typ reflect.Type) (*orderedJSONObject, error) {
+	var (
+		properties = &orderedJSONObject{}
+		required   []interface{}
+	)
+	for _, field := range jsonStructFields(typ) {
+		property, err := g.fieldSchema(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typ, field.name, err)
+		}
+		properties.set(field.name, property)
+		if field.required || !field.omitEmpty {
+			required = append(required, field.name)
+		}
+	}
+
+	schema := schemaObject("type", "object")
+	if len(properties.keys) > 0 {
+		schema.set("properties", properties)
+	}
+	if len(required) > 0 {
+		schema.set("required", required)
+	}
+	return schema, nil
+}
+
+func (g *schemaGenerator) fieldSchema(field jsonField) (*orderedJSONObject, error) {
+	var (
+		schema *orderedJSONObject
+		err    error
+	)
+	if field.quoted {
+		// `json:",string"` 将基础类型编码为字符串
+		schema = schemaObject("type", "string")
+	} else if schema, err = g.generate(field.typ); err != nil {
+		return nil, err
+	}
+
+	if tag, ok := field.tag.Lookup("jsonschema"); ok {
+		if err = applySchemaTag(schema, tag); err != nil {
+			return nil, err
+		}
+	}
+
+	switch field.typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+	default:
+		return schema, nil
+	}
+	if !field.omitEmpty {
+		if typ, ok := schema.get("type"); ok {
+			schema.set("type", []interface{}{typ, "null"})
+			if enum, ok := schema.get("enum"); ok {
+				schema.set("enum", append(enum.([]interface{}), nil))
+			}
+		} else if len(schema.keys) > 0 {
+			schema = schemaObject("anyOf", []interface{}{schema, schemaObject("type", "null")})
+		}
+	}
+	return schema, nil
+}
+
+// applySchemaTag 将 jsonschema 标签中的约束追加到 schema
+func applySchemaTag(schema *orderedJSONObject, tag string) error {
+	for tag != "" {
+		var item string
+		item, tag, _ = strings.Cut(tag, ",")
+		if item == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(item, "=")
+		switch key {
+		case "title", "description", "pattern":
+			// 取值可能包含逗号（如 ^a{1,3}$），取标签的剩余部分
+			if tag != "" {
+				value += "," + tag
+				tag = ""
+			}
+			schema.set(key, value)
+		case "format":
+			schema.set(key, value)
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("%w: %s must be a number, got %q", ErrInvalidSchemaTag, key, value)
+			}
+			schema.set(key, json.Number(value))
+		case "minLength", "maxLength", "minItems", "maxItems":
+			if count, err := strconv.Atoi(value); err != nil || count < 0 {
+				return fmt.Errorf("%w: %s must be a non-negative integer, got %q", ErrInvalidSchemaTag, key, value)
+			}
+			schema.set(key, json.Number(value))
+		case "enum":
+			typ, _ := schema.get("type")
+			var enum []interface{}
+			for _, option := range strings.Split(value, "|") {
+				converted, err := schemaEnumValue(typ, option)
+				if err != nil {
+					return err
+				}
+				enum = append(enum, converted)
+			}
+			schema.set(key, enum)
+		default:
+			return fmt.Errorf("%w: unknown constraint %q", ErrInvalidSchemaTag, key)
+		}
+	}
+	return nil
+}
+
+// schemaEnumValue 按 schema 的类型转换 enum 取值
+func schemaEnumValue(typ interface{}, option string) (interface{}, error) {
+	switch typ {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(option, 64); err != nil {
+			return nil, fmt.Errorf("%w: enum value %q is not a number", ErrInvalidSchemaTag, option)
+		}
+		return json.Number(option), nil
+	case "boolean":
+		value, err := strconv.ParseBool(option)
+		if err != nil {
+			return nil, fmt.Errorf("%w: enum value %q is not a boolean", ErrInvalidSchemaTag, option)
+		}
+		return value, nil
+	default:
+		return option, nil
+	}
+}
+
+// isSchemaSpecialType 判断结构体类型是否按特殊规则生成（而非展开字段）
+func isSchemaSpecialType(typ reflect.Type) bool {
+	return typ == _timeType ||
+		typ.Implements(_jsonMarshalerType) || reflect.PtrTo(typ).Implements(_jsonMarshalerType) ||
+		typ.Implements(_textMarshalerType) || reflect.PtrTo(typ).Implements(_textMarshalerType)
+}
+
+// schemaObject 按 key、value 交替的参数构造有序对象
+func schemaObject(pairs ...interface{}) *orderedJSONObject {
+	object := &orderedJSONObject{}
+	for idx := 0; idx+1 < len(pairs); idx += 2 {
+		object.set(pairs[idx].(string), pairs[idx+1])
+	}
+	return object
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type JSONSchemaGenTest struct {
+	suite.Suite
+}
+
+func TestJSONSchemaGenTest(t *testing.T) {
+	suite.Run(t, new(JSONSchemaGenTest))
+}
+
+type schemaGenBase struct {
+	ID        int64     `json:"id,string"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type schemaGenAddress struct {
+	City string `json:"city" jsonschema:"minLength=1"`
+}
+
+type schemaGenUser struct {
+	schemaGenBase
+	Name     string                      `json:"name" jsonschema:"minLength=2,maxLength=16,description=用户名"`
+	Age      uint8                       `json:"age" jsonschema:"maximum=150"`
+	Role     string                      `json:"role,omitempty" jsonschema:"enum=admin|user"`
+	Email    *string                     `json:"email"`
+	Address  *schemaGenAddress           `json:"address,omitempty"`
+	Backup   *schemaGenAddress           `json:"backup"`
+	Tags     []string                    `json:"tags,omitempty" jsonschema:"maxItems=3"`
+	Scores   map[string]float64          `json:"scores,omitempty"`
+	Avatar   []byte                      `json:"avatar,omitempty"`
+	Extra    interface{}                 `json:"extra,omitempty"`
+	Raw      json.RawMessage             `json:"raw,omitempty"`
+	Point    [2]int                      `json:"point"`
+	Friends  []schemaGenUser             `json:"friends,omitempty"`
+	Settings struct{ Dark bool }         `json:"settings"`
+	Homes    map[string]schemaGenAddress `json:"homes,omitempty"`
+	Secret   string                      `json:"-"`
+	internal string
+}
+
+func (j *JSONSchemaGenTest) TestGopherunJSON_GenerateSchema_case1() {
+	schema, err := JSON.GenerateSchema(&schemaGenUser{})
+	require.Nil(j.T(), err)
+	require.JSONEq(j.T(), `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"id": {"type": "string"},
+			"createdAt": {"type": "string", "format": "date-time"},
+			"name": {"type": "string", "minLength": 2, "maxLength": 16, "description": "用户名"},
+			"age": {"type": "integer", "minimum": 0, "maximum": 150},
+			"role": {"type": "string", "enum": ["admin", "user"]},
+			"email": {"type": ["string", "null"]},
+			"address": {"$ref": "#/$defs/schemaGenAddress"},
+			"backup": {"anyOf": [{"$ref": "#/$defs/schemaGenAddress"}, {"type": "null"}]},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 3},
+			"scores": {"type": "object", "additionalProperties": {"type": "number"}},
+			"avatar": {"type": "string", "contentEncoding": "base64"},
+			"extra": {},
+			"raw": {},
+			"point": {"type": "array", "items": {"type": "integer"}, "minItems": 2, "maxItems": 2},
+			"friends": {"type": "array", "items": {"$ref": "#"}},
+			"settings": {"type": "object", "properties": {"Dark": {"type": "boolean"}}, "required": ["Dark"]},
+			"homes": {"type": "object", "additionalProperties": {"$ref": "#/$defs/schemaGenAddress"}}
+		},
+		"required": ["id", "createdAt", "name", "age", "email", "backup", "point", "settings"],
+		"$defs": {
+			"schemaGenAddress": {
+				"type": "object",
+				"properties": {"city": {"type": "string", "minLength": 1}},
+				"required": ["city"]
+			}
+		}
+	}`, string(schema))
+
+	// 生成的 schema 可以校验 Encode 的输出
+	compiled, err := JSON.CompileSchema(schema)
+	require.Nil(j.T(), err)
+	user := schemaGenUser{
+		Name:    "zhangsan",
+		Age:     12,
+		Role:    "admin",
+		Address: &schemaGenAddress{City: "beijing"},
+		Friends: []schemaGenUser{{Name: "lisi"}},
+	}
+	require.Nil(j.T(), compiled.ValidateValue(user))
+
+	user.Role = "guest"
+	user.Friends[0].Name = "x"
+	err = compiled.ValidateValue(user)
+	var validationErr *JSONValidationError
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Len(j.T(), validationErr.Violations, 2)
+	require.Equal(j.T(), "/friends/0/name", validationErr.Violations[0].Path)
+	require.Equal(j.T(), "/role", validationErr.Violations[1].Path)
+}
+
+func (j *JSONSchemaGenTest) TestGopherunJSON_GenerateSchema_case2() {
+	schema, err := JSON.GenerateSchema([]int{})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"array","items":{"type":"integer"}}`, string(schema))
+
+	// 传入 reflect.Type 与传入值等价
+	type Item struct {
+		Level int   `json:"level" required:"true" jsonschema:"enum=1|2|3"`
+		Ok    *bool `json:"ok" jsonschema:"enum=true"`
+		Count int   `json:"count,omitempty" required:"true"`
+	}
+	schema, err = JSON.GenerateSchema(reflect.TypeOf(Item{}))
+	require.Nil(j.T(), err)
+	require.JSONEq(j.T(), `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"level": {"type": "integer", "enum": [1, 2, 3]},
+			"ok": {"type": ["boolean", "null"], "enum": [true, null]},
+			"count": {"type": "integer"}
+		},
+		"required": ["level", "ok", "count"]
+	}`, string(schema))
+
+	schema, err = JSON.GenerateSchema(time.Time{})
+	require.Nil(j.T(), err)
+	require.JSONEq(j.T(), `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"string","format":"date-time"}`, string(schema))
+}
+
+func (j *JSONSchemaGenTest) TestGopherunJSON_GenerateSchema_case3() {
+	_, err := JSON.GenerateSchema(nil)
+	require.True(j.T(), errors.Is(err, ErrUnsupportedSchemaType))
+	_, err = JSON.GenerateSchema(make(chan int))
+	require.True(j.T(), errors.Is(err, ErrUnsupportedSchemaType))
+	_, err = JSON.GenerateSchema(map[[2]int]string{})
+	require.True(j.T(), errors.Is(err, ErrUnsupportedSchemaType))
+
+	type BadField struct {
+		Callback func() `json:"callback"`
+	}
+	_, err = JSON.GenerateSchema(BadField{})
+	require.True(j.T(), errors.Is(err, ErrUnsupportedSchemaType))
+
+	cases := []interface{}{
+		struct {
+			A int `jsonschema:"minimum=abc"`
+		}{},
+		struct {
+			A string `jsonschema:"maxLength=-1"`
+		}{},
+		struct {
+			A int `jsonschema:"enum=1|x"`
+		}{},
+		struct {
+			A bool `jsonschema:"enum=yes"`
+		}{},
+		struct {
+			A string `jsonschema:"unknown=1"`
+		}{},
+	}
+	for _, c := range cases {
+		_, err = JSON.GenerateSchema(c)
+		require.True(j.T(), errors.Is(err, ErrInvalidSchemaTag), "%T: %v", c, err)
+	}
+}
+
+func (j *JSONSchemaGenTest) TestGopherunJSON_GenerateSchema_case4() {
+	type document struct {
+		Tags   []string          `json:"tags"`
+		Labels map[string]string `json:"labels"`
+		Avatar []byte            `json:"avatar"`
+		Code   string            `json:"code" jsonschema:"maxLength=3,pattern=^[a-z]{1,3}$"`
+		Note   string            `json:"note" jsonschema:"description=备注, 可以为空"`
+	}
+
+	schema, err := JSON.GenerateSchema(document{})
+	require.Nil(j.T(), err)
+	require.JSONEq(j.T(), `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"tags": {"type": ["array", "null"], "items": {"type": "string"}},
+			"labels": {"type": ["object", "null"], "additionalProperties": {"type": "string"}},
+			"avatar": {"type": ["string", "null"], "contentEncoding": "base64"},
+			"code": {"type": "string", "maxLength": 3, "pattern": "^[a-z]{1,3}$"},
+			"note": {"type": "string", "description": "备注, 可以为空"}
+		},
+		"required": ["tags", "labels", "avatar", "code", "note"]
+	}`, string(schema))
+
+	// 零值中的 nil 切片与 map 编码为 null，同样满足生成的 schema
+	compiled, err := JSON.CompileSchema(schema)
+	require.Nil(j.T(), err)
+	require.Nil(j.T(), compiled.ValidateValue(document{Code: "abc"}))
+	require.NotNil(j.T(), compiled.ValidateValue(document{Code: "ab1"}))
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type JSONSchemaTest struct {
+	suite.Suite
+}
+
+func TestJSONSchemaTest(t *testing.T) {
+	suite.Run(t, new(JSONSchemaTest))
+}
+
+const _testUserSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["name", "age"],
+	"properties": {
+		"name": {"type": "string", "minLength": 2, "maxLength": 8, "pattern": "^[a-z]+$"},
+		"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+		"role": {"enum": ["admin", "user"]},
+		"kind": {"const": "person"},
+		"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1, "maxItems": 3},
+		"address": {"$ref": "#/$defs/address"}
+	},
+	"additionalProperties": false,
+	"$defs": {
+		"address": {
+			"type": "object",
+			"required": ["city"],
+			"properties": {"city": {"type": "string"}}
+		}
+	}
+}`
+
+func (j *JSONSchemaTest) TestGopherunJSON_CompileSchema_case1() {
+	schema, err := JSON.CompileSchema([]byte(_testUserSchema))
+	require.Nil(j.T(), err)
+
+	require.Nil(j.T(), schema.Validate([]byte(`{"name":"zhangsan","age":12,"role":"admin","kind":"person","tags":["a"],"address":{"city":"beijing"}}`)))
+	require.Nil(j.T(), schema.Validate([]byte(`{"name":"li","age":12.0}`)))
+
+	// 收集全部违规项，路径为实例中的位置
+	err = schema.Validate([]byte(`{"name":"Zhang San Feng","age":150,"role":"guest","kind":"robot","tags":[1,"b","c","d"],"address":{},"email":"a@b.c"}`))
+	var validationErr *JSONValidationError
+	require.True(j.T(), errors.As(err, &validationErr))
+
+	paths := map[string]int{}
+	for _, violation := range validationErr.Violations {
+		require.Equal(j.T(), JSONViolationSchema, violation.Kind)
+		paths[violation.Path]++
+	}
+	require.Equal(j.T(), map[string]int{
+		"/name":         2, // maxLength 与 pattern
+		"/age":          1,
+		"/role":         1,
+		"/kind":         1,
+		"/tags":         1,
+		"/tags/0":       1,
+		"/address/city": 1,
+		"/email":        1,
+	}, paths)
+	require.Contains(j.T(), err.Error(), `/email: additionalProperties: property "email" is not allowed`)
+	require.Contains(j.T(), err.Error(), `/address/city: required: missing property "city"`)
+	require.Contains(j.T(), err.Error(), `/age: exclusiveMaximum: 150 is not less than 150`)
+
+	// 缺少必填字段、类型错误
+	err = schema.Validate([]byte(`{"age":1.5}`))
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), []JSONViolation{
+		{Path: "/name", Kind: JSONViolationSchema, Message: `required: missing property "name"`},
+		{Path: "/age", Kind: JSONViolationSchema, Message: "type: expected integer, got number"},
+	}, validationErr.Violations)
+
+	err = schema.Validate([]byte(`[]`))
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), "", validationErr.Violations[0].Path)
+
+	require.NotNil(j.T(), schema.Validate([]byte(`{`)))
+}
+
+func (j *JSONSchemaTest) TestGopherunJSON_CompileSchema_case2() {
+	// 组合关键字与递归引用
+	schema, err := JSON.CompileSchema([]byte(`{
+		"$defs": {
+			"node": {
+				"type": "object",
+				"properties": {
+					"value": {"oneOf": [{"type": "integer"}, {"type": "number", "maximum": 5}]},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}
+				}
+			}
+		},
+		"allOf": [{"$ref": "#/$defs/node"}],
+		"anyOf": [{"required": ["value"]}, {"required": ["children"]}],
+		"not": {"required": ["forbidden"]}
+	}`))
+	require.Nil(j.T(), err)
+
+	require.Nil(j.T(), schema.Validate([]byte(`{"value":12,"children":[{"value":2.5},{"children":[{"value":12}]}]}`)))
+	require.Nil(j.T(), schema.Validate([]byte(`{"value":1.5}`)))
+
+	var validationErr *JSONValidationError
+	err = schema.Validate([]byte(`{"value":3}`))
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), "/value", validationErr.Violations[0].Path)
+	require.Equal(j.T(), "oneOf: value matches 2 schemas, expected exactly 1", validationErr.Violations[0].Message)
+
+	err = schema.Validate([]byte(`{"children":[{"children":[{"value":"x"}]}]}`))
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), "/children/0/children/0/value", validationErr.Violations[0].Path)
+
+	err = schema.Validate([]byte(`{"forbidden":true}`))
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), []JSONViolation{
+		{Path: "", Kind: JSONViolationSchema, Message: "anyOf: value does not match any schema"},
+		{Path: "", Kind: JSONViolationSchema, Message: "not: value must not match the schema"},
+	}, validationErr.Violations)
+
+	// prefixItems 与布尔 schema
+	schema, err = JSON.CompileSchema([]byte(`{"type":["array","null"],"prefixItems":[{"type":"string"},true],"items":false}`))
+	require.Nil(j.T(), err)
+	require.Nil(j.T(), schema.Validate([]byte(`null`)))
+	require.Nil(j.T(), schema.Validate([]byte(`["a",{}]`)))
+	err = schema.Validate([]byte(`["a",1,2]`))
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), "/2", validationErr.Violations[0].Path)
+}
+
+func (j *JSONSchemaTest) TestGopherunJSON_CompileSchema_case3() {
+	cases := []string{
+		`{`,
+		`1`,
+		`{"type":"text"}`,
+		`{"type":1}`,
+		`{"required":"name"}`,
+		`{"minimum":"1"}`,
+		`{"minLength":-1}`,
+		`{"pattern":"("}`,
+		`{"allOf":[]}`,
+		`{"properties":{"a":1}}`,
+		`{"$ref":"#/$defs/missing"}`,
+		`{"$ref":"https://example.com/schema.json"}`,
+		// 不消耗实例的引用环
+		`{"$ref":"#"}`,
+		`{"$defs":{"a":{"$ref":"#/$defs/a"}},"$ref":"#/$defs/a"}`,
+		`{"$defs":{"a":{"$ref":"#/$defs/b"},"b":{"allOf":[{"$ref":"#/$defs/a"}]}},"properties":{"x":{"$ref":"#/$defs/a"}}}`,
+	}
+	for _, c := range cases {
+		_, err := JSON.CompileSchema([]byte(c))
+		require.True(j.T(), errors.Is(err, ErrInvalidSchema), "%s: %v", c, err)
+	}
+}
+
+func (j *JSONSchemaTest) TestGopherunJSON_CompileSchema_case4() {
+	// 经过 properties、items 的递归引用是合法的
+	schema, err := JSON.CompileSchema([]byte(`{
+		"$defs": {"node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}}},
+		"$ref": "#/$defs/node"
+	}`))
+	require.Nil(j.T(), err)
+	require.Nil(j.T(), schema.Validate([]byte(`{"children":[{"children":[]},{}]}`)))
+	require.NotNil(j.T(), schema.Validate([]byte(`{"children":[{"children":1}]}`)))
+}
+
+func (j *JSONSchemaTest) TestGopherunJSON_ValidateSchema() {
+	schema := []byte(`{"type":"object","properties":{"id":{"type":"integer","maximum":9007199254740993}}}`)
+	require.Nil(j.T(), JSON.ValidateSchema(schema, []byte(`{"id":9007199254740993}`)))
+	require.NotNil(j.T(), JSON.ValidateSchema(schema, []byte(`{"id":9007199254740994}`)))
+	require.True(j.T(), errors.Is(JSON.ValidateSchema([]byte(`{`), nil), ErrInvalidSchema))
+
+	compiled, err := JSON.CompileSchema(schema)
+	require.Nil(j.T(), err)
+	type Item struct {
+		ID int64 `json:"id"`
+	}
+	require.Nil(j.T(), compiled.ValidateValue(Item{ID: 1}))
+	require.NotNil(j.T(), compiled.ValidateValue(map[string]interface{}{"id": "1"}))
+	require.NotNil(j.T(), compiled.ValidateValue(make(chan int)))
+}
@@ -0,0 +1,278 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrLockTimeout 等待文件锁超时
+	ErrLockTimeout = errors.New("timeout waiting for file lock")
+
+	// ErrFileLockUnsupported 当前平台不支持跨进程文件锁（如 js/wasm、plan9）
+	ErrFileLockUnsupported = errors.New("file lock is not supported on this platform")
+)
+
+// JSONStore 以 JSON 文件持久化一个类型为 T 的值。
+// 读取时文件不存在则返回默认值；写入通过 WriteFileSafer 原子落盘，并对 path.lock 文件加操作系统文件锁
+// （flock / LockFileEx，Solaris、illumos 与 AIX 上为 fcntl）实现跨进程互斥，持有者退出时锁自动释放，锁文件本身会保留；
+// 不支持文件锁的平台上读写返回 ErrFileLockUnsupported。
+// 零值 &JSONStore[T]{Path: path} 可以直接使用，未设置的字段使用默认值；
+// 创建后可直接修改导出字段进行配置，但不应在并发使用期间修改。
+type JSONStore[T any] struct {
+	// Path JSON 文件路径
+	Path string
+
+	// Perm 写入文件的权限，默认 0644
+	Perm os.FileMode
+
+	// Default 文件不存在时用于生成默认值，为 nil 时使用 T 的零值
+	Default func() T
+
+	// Backup 为 true 时，每次保存前将旧文件复制为 path.bak
+	Backup bool
+
+	// Version 当前数据结构版本号，大于 0 时会写入到 VersionKey 字段并在读取时执行迁移
+	Version int
+
+	// VersionKey 版本号在 JSON 顶层对象中的字段名，默认 "schemaVersion"
+	VersionKey string
+
+	// Migrations 版本迁移函数，键为源版本号，函数负责将文档从版本 n 升级到 n+1；
+	// 没有版本字段的旧文件视为版本 0
+	Migrations map[int]func(doc map[string]interface{}) error
+
+	// LockTimeout 获取文件锁的最长等待时间，默认 10s
+	LockTimeout time.Duration
+
+	mu sync.Mutex
+}
+
+// NewJSONStore 创建一个持久化到 path 的 JSONStore
+func NewJSONStore[T any](path string) *JSONStore[T] {
+	return &JSONStore[T]{
+		Path:        path,
+		Perm:        0644,
+		VersionKey:  "schemaVersion",
+		LockTimeout: 10 * time.Second,
+	}
+}
+
+// Load 读取并返回文件中的值，文件不存在时返回默认值
+func (s *JSONStore[T]) Load() (T, error) {
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return s.defaultValue(), nil
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return s.decode(data)
+}
+
+// Save 加锁后将 value 原子写入文件
+func (s *JSONStore[T]) Save(value T) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.save(value)
+}
+
+// Update 加锁后读取当前值并交给 fn 修改，fn 返回 nil 时原子写回文件；
+// fn 返回错误时放弃修改并原样返回该错误。
+func (s *JSONStore[T]) Update(fn func(value *T) error) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	value, err := s.Load()
+	if err != nil {
+		return err
+	}
+	if err = fn(&value); err != nil {
+		return err
+	}
+	return s.save(value)
+}
+
+func (s *JSONStore[T]) perm() os.FileMode {
+	if s.Perm == 0 {
+		return 0644
+	}
+	return s.Perm
+}
+
+func (s *JSONStore[T]) versionKey() string {
+	if s.VersionKey == "" {
+		return "schemaVersion"
+	}
+	return s.VersionKey
+}
+
+func (s *JSONStore[T]) lockTimeout() time.Duration {
+	if s.LockTimeout <= 0 {
+		return 10 * time.Second
+	}
+	return s.LockTimeout
+}
+
+func (s *JSONStore[T]) defaultValue() T {
+	if s.Default != nil {
+		return s.Default()
+	}
+	var zero T
+	return zero
+}
+
+// decode 执行版本迁移后将数据解码到 T
+func (s *JSONStore[T]) decode(data []byte) (T, error) {
+	value := s.defaultValue()
+	if s.Version <= 0 {
+		return value, JSON.Decode(data, &value)
+	}
+
+	// 使用 UseNumber 避免大整数在迁移过程中丢失精度
+	var doc map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return value, err
+	}
+
+	version, err := s.docVersion(doc)
+	if err != nil {
+		return value, err
+	}
+	if version > s.Version {
+		return value, fmt.Errorf("%s: schema version %d is newer than supported version %d", s.Path, version, s.Version)
+	}
+	if version == s.Version {
+		return value, JSON.Decode(data, &value)
+	}
+
+	for ; version < s.Version; version++ {
+		migrate, ok := s.Migrations[version]
+		if !ok {
+			return value, fmt.Errorf("%s: no migration from schema version %d", s.Path, version)
+		}
+		if err = migrate(doc); err != nil {
+			return value, fmt.Errorf("%s: migrate from schema version %d: %w", s.Path, version, err)
+		}
+	}
+
+	migrated, err := JSON.Encode(doc)
+	if err != nil {
+		return value, err
+	}
+	return value, JSON.Decode(migrated, &value)
+}
+
+func (s *JSONStore[T]) docVersion(doc map[string]interface{}) (int, error) {
+	key := s.versionKey()
+	raw, ok := doc[key]
+	if !ok {
+		return 0, nil
+	}
+	number, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("%s: field %q is not a number", s.Path, key)
+	}
+	version, err := number.Int64()
+	if err != nil {
+		return 0, fmt.Errorf("%s: field %q: %w", s.Path, key, err)
+	}
+	return int(version), nil
+}
+
+// encode 编码 value，设置了 Version 时将版本号写入顶层对象
+func (s *JSONStore[T]) encode(value T) ([]byte, error) {
+	data, err := JSON.Encode(value)
+	if err != nil || s.Version <= 0 {
+		return data, err
+	}
+
+	var doc map[string]json.RawMessage
+	if err = JSON.Decode(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: versioned value must encode as a JSON object: %w", s.Path, err)
+	}
+	if doc == nil {
+		doc = map[string]json.RawMessage{}
+	}
+	doc[s.versionKey()] = json.RawMessage(fmt.Sprint(s.Version))
+	return JSON.Encode(doc)
+}
+
+func (s *JSONStore[T]) save(value T) error {
+	data, err := s.encode(value)
+	if err != nil {
+		return err
+	}
+
+	if s.Backup {
+		if err = backupFile(s.Path, "", s.perm()); err != nil {
+			return err
+		}
+	}
+
+	return File.WriteFileSafer(s.Path, data, s.perm())
+}
+
+// lock 获取进程内互斥锁与跨进程文件锁，返回释放函数。
+// 锁文件不会被删除：删除已加锁的文件会让其他进程锁住不同的文件而同时进入临界区。
+func (s *JSONStore[T]) lock() (func(), error) {
+	s.mu.Lock()
+
+	lockPath := s.Path + ".lock"
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	deadline := time.Now().Add(s.lockTimeout())
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			_ = f.Close()
+			s.mu.Unlock()
+			return nil, err
+		}
+		if locked {
+			return func() {
+				_ = unlockFile(f)
+				_ = f.Close()
+				s.mu.Unlock()
+			}, nil
+		}
+
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			s.mu.Unlock()
+			return nil, fmt.Errorf("%w: %s", ErrLockTimeout, lockPath)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type JSONStoreTest struct {
+	BaseTest
+}
+
+func TestJSONStoreTest(t *testing.T) {
+	suite.Run(t, new(JSONStoreTest))
+}
+
+type storeSettings struct {
+	Name    string `json:"name"`
+	Counter int    `json:"counter"`
+	Theme   string `json:"theme"`
+}
+
+func (j *JSONStoreTest) TestJSONStore_Load_case1() {
+	store := NewJSONStore[storeSettings](filepath.Join(j.T().TempDir(), "settings.json"))
+	store.Default = func() storeSettings {
+		return storeSettings{Theme: "dark"}
+	}
+
+	// 文件不存在时返回默认值
+	settings, err := store.Load()
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), storeSettings{Theme: "dark"}, settings)
+	require.NoFileExists(j.T(), store.Path)
+
+	// 文件中缺失的字段保留默认值
+	require.Nil(j.T(), os.WriteFile(store.Path, []byte(`{"name":"gopher"}`), 0644))
+	settings, err = store.Load()
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), storeSettings{Name: "gopher", Theme: "dark"}, settings)
+}
+
+func (j *JSONStoreTest) TestJSONStore_Load_case2() {
+	store := NewJSONStore[storeSettings](filepath.Join(j.T().TempDir(), "settings.json"))
+	require.Nil(j.T(), os.WriteFile(store.Path, []byte(`{"name":`), 0644))
+
+	_, err := store.Load()
+	require.NotNil(j.T(), err)
+}
+
+func (j *JSONStoreTest) TestJSONStore_Update_case1() {
+	store := NewJSONStore[storeSettings](filepath.Join(j.T().TempDir(), "settings.json"))
+	store.Backup = true
+
+	var wg sync.WaitGroup
+	for n := 0; n < 10; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := store.Update(func(settings *storeSettings) error {
+				settings.Counter++
+				return nil
+			})
+			require.Nil(j.T(), err)
+		}()
+	}
+	wg.Wait()
+
+	settings, err := store.Load()
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), 10, settings.Counter)
+
+	// 备份文件保存的是上一个版本
+	backup := NewJSONStore[storeSettings](store.Path + ".bak")
+	previous, err := backup.Load()
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), 9, previous.Counter)
+}
+
+func (j *JSONStoreTest) TestJSONStore_Update_case2() {
+	store := NewJSONStore[storeSettings](filepath.Join(j.T().TempDir(), "settings.json"))
+	require.Nil(j.T(), store.Save(storeSettings{Name: "before"}))
+
+	// fn 返回错误时不写回
+	mockErr := errors.New("mock err")
+	err := store.Update(func(settings *storeSettings) error {
+		settings.Name = "after"
+		return mockErr
+	})
+	require.Equal(j.T(), mockErr, err)
+
+	settings, err := store.Load()
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "before", settings.Name)
+}
+
+func (j *JSONStoreTest) TestJSONStore_Lock() {
+	store := NewJSONStore[storeSettings](filepath.Join(j.T().TempDir(), "settings.json"))
+	store.LockTimeout = 50 * time.Millisecond
+
+	// 未加锁的残留锁文件不影响获取锁
+	require.Nil(j.T(), os.WriteFile(store.Path+".lock", nil, 0600))
+	require.Nil(j.T(), store.Save(storeSettings{Name: "gopher"}))
+
+	// 其他句柄（进程）持有锁时超时
+	holder, err := os.OpenFile(store.Path+".lock", os.O_RDWR, 0600)
+	require.Nil(j.T(), err)
+	defer holder.Close()
+	locked, err := tryLockFile(holder)
+	require.Nil(j.T(), err)
+	require.True(j.T(), locked)
+	err = store.Save(storeSettings{})
+	require.True(j.T(), errors.Is(err, ErrLockTimeout))
+
+	// 持有者释放后可以获取锁
+	require.Nil(j.T(), unlockFile(holder))
+	require.Nil(j.T(), store.Save(storeSettings{Name: "gopher"}))
+	require.FileExists(j.T(), store.Path)
+}
+
+func (j *JSONStoreTest) TestJSONStore_ZeroValue() {
+	// 零值使用默认的权限、版本字段与锁等待时间
+	store := &JSONStore[storeSettings]{Path: filepath.Join(j.T().TempDir(), "settings.json"), Version: 1}
+	require.Nil(j.T(), store.Update(func(settings *storeSettings) error {
+		settings.Name = "gopher"
+		return nil
+	}))
+
+	info, err := os.Stat(store.Path)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), os.FileMode(0600), info.Mode().Perm()&0600)
+
+	data, err := os.ReadFile(store.Path)
+	require.Nil(j.T(), err)
+	require.Contains(j.T(), string(data), `"schemaVersion":1`)
+
+	settings, err := store.Load()
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "gopher", settings.Name)
+}
+
+func (j *JSONStoreTest) TestJSONStore_Migrations_case1() {
+	type settingsV2 struct {
+		FullName string `json:"fullName"`
+		Counter  int64  `json:"counter"`
+	}
+	store := NewJSONStore[settingsV2](filepath.Join(j.T().TempDir(), "settings.json"))
+	store.Version = 2
+	store.Migrations = map[int]func(doc map[string]interface{}) error{
+		0: func(doc map[string]interface{}) error {
+			doc["fullName"] = doc["name"]
+			delete(doc, "name")
+			return nil
+		},
+		1: func(doc map[string]interface{}) error {
+			doc["fullName"] = doc["fullName"].(string) + "!"
+			return nil
+		},
+	}
+
+	require.Nil(j.T(), os.WriteFile(store.Path, []byte(`{"name":"gopher","counter":9007199254740993}`), 0644))
+	settings, err := store.Load()
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), settingsV2{FullName: "gopher!", Counter: 9007199254740993}, settings)
+
+	// 保存时写入版本号，再次读取不会重复迁移
+	require.Nil(j.T(), store.Save(settings))
+	data, err := os.ReadFile(store.Path)
+	require.Nil(j.T(), err)
+	require.JSONEq(j.T(), `{"schemaVersion":2,"fullName":"gopher!","counter":9007199254740993}`, string(data))
+
+	settings, err = store.Load()
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), "gopher!", settings.FullName)
+}
+
+func (j *JSONStoreTest) TestJSONStore_Migrations_case2() {
+	store := NewJSONStore[storeSettings](filepath.Join(j.T().TempDir(), "settings.json"))
+	store.Version = 2
+
+	// 缺少迁移函数
+	require.Nil(j.T(), os.WriteFile(store.Path, []byte(`{"schemaVersion":1}`), 0644))
+	_, err := store.Load()
+	require.NotNil(j.T(), err)
+
+	// 文件版本高于当前版本
+	require.Nil(j.T(), os.WriteFile(store.Path, []byte(`{"schemaVersion":3}`), 0644))
+	_, err = store.Load()
+	require.NotNil(j.T(), err)
+
+	// 版本字段类型错误
+	require.Nil(j.T(), os.WriteFile(store.Path, []byte(`{"schemaVersion":"2"}`), 0644))
+	_, err = store.Load()
+	require.NotNil(j.T(), err)
+
+	// 迁移函数返回错误
+	store.Migrations = map[int]func(doc map[string]interface{}) error{
+		1: func(doc map[string]interface{}) error {
+			return errors.New("mock err")
+		},
+	}
+	require.Nil(j.T(), os.WriteFile(store.Path, []byte(`{"schemaVersion":1}`), 0644))
+	_, err = store.Load()
+	require.NotNil(j.T(), err)
+}
@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrStreamStop 在流式解码回调中返回该错误可提前结束遍历，遍历函数本身返回 nil
+var ErrStreamStop = errors.New("stop stream")
+
+// JSONStreamOptions 流式解码配置
+type JSONStreamOptions struct {
+	// SkipBadRecords 为 true 时跳过无法解码的记录继续处理，否则遇到第一条坏记录即返回错误。
+	// 对于 JSON 数组，只有类型不匹配等语义错误可以跳过，语法错误会导致后续数据无法定位，总是返回错误。
+	SkipBadRecords bool
+
+	// OnBadRecord 跳过坏记录时的回调，可用于记录日志或计数
+	OnBadRecord func(err *JSONStreamError)
+}
+
+// JSONStreamError 流式解码中单条记录的错误及其在输入中的位置
+type JSONStreamError struct {
+	Index  int   // 记录序号，从 0 开始
+	Line   int   // 行号，从 1 开始
+	Column int   // 列号（字节），从 1 开始
+	Offset int64 // 相对于输入开头的字节偏移量
+	Err    error // 原始错误
+}
+
+func (e *JSONStreamError) Error() string {
+	return fmt.Sprintf("record %d at line %d, column %d (offset %d): %v", e.Index, e.Line, e.Column, e.Offset, e.Err)
+}
+
+func (e *JSONStreamError) Unwrap() error {
+	return e.Err
+}
+
+// StreamArray 逐个读取顶层 JSON 数组中的元素，以原始 JSON 的形式交给 fn，不会一次性读入整个文档
+func (i GopherunJSON) StreamArray(r io.Reader, fn func(raw json.RawMessage) error, opts JSONStreamOptions) error {
+	return JSONStreamArray(r, fn, opts)
+}
+
+// StreamLines 逐行读取 NDJSON（JSON Lines），以原始 JSON 的形式交给 fn，空行会被忽略
+func (i GopherunJSON) StreamLines(r io.Reader, fn func(raw json.RawMessage) error, opts JSONStreamOptions) error {
+	return JSONStreamLines(r, fn, opts)
+}
+
+// JSONStreamArray 逐个将顶层 JSON 数组中的元素解码为 T 并交给 fn。
+// fn 返回 ErrStreamStop 时提前结束并返回 nil，返回其他错误时原样返回。
+func JSONStreamArray[T any](r io.Reader, fn func(item T) error, opts JSONStreamOptions) error {
+	tracker := &lineTracker{r: r, lastLF: -1}
+	decoder := json.NewDecoder(tracker)
+
+	if err := expectArrayStart(decoder, tracker); err != nil {
+		return err
+	}
+
+	for index := 0; decoder.More(); index++ {
+		// 丢弃当前记录之前的换行符位置，避免长时间运行时占用内存
+		tracker.position(decoder.InputOffset())
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return tracker.streamError(index, syntaxErrorOffset(err, decoder.InputOffset()), err)
+		}
+
+		var item T
+		if err := decodeJSONRecord(raw, &item); err != nil {
+			streamErr := tracker.streamError(index, decoder.InputOffset()-int64(len(raw))+recordErrorOffset(err), err)
+			if err = handleBadRecord(streamErr, opts); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := fn(item); err != nil {
+			if errors.Is(err, ErrStreamStop) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return tracker.streamError(-1, syntaxErrorOffset(err, decoder.InputOffset()), err)
+	}
+	return nil
+}
+
+// JSONStreamLines 逐行将 NDJSON（JSON Lines）记录解码为 T 并交给 fn，空行会被忽略。
+// fn 返回 ErrStreamStop 时提前结束并返回 nil，返回其他错误时原样返回。
+func JSONStreamLines[T any](r io.Reader, fn func(item T) error, opts JSONStreamOptions) error {
+	reader := bufio.NewReader(r)
+
+	var (
+		offset int64
+		index  int
+	)
+	for lineNo := 1; ; lineNo++ {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+
+		lineStart := offset
+		offset += int64(len(line))
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var item T
+			if err := decodeJSONRecord(trimmed, &item); err != nil {
+				column := bytes.Index(line, trimmed) + 1 + int(recordErrorOffset(err))
+				streamErr := &JSONStreamError{Index: index, Line: lineNo, Column: column, Offset: lineStart + int64(column-1), Err: err}
+				if err = handleBadRecord(streamErr, opts); err != nil {
+					return err
+				}
+			} else if err = fn(item); err != nil {
+				if errors.Is(err, ErrStreamStop) {
+					return nil
+				}
+				return err
+			}
+			index++
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+// decodeJSONRecord 使用 SetCodec 设置的引擎解码一条记录，包含多态类型时按区分字段解码。
+// 位置信息由 JSONStreamError 给出；经过多态解码时错误为 *JSONDecodeError，偏移量相对于记录开头
+func decodeJSONRecord(data []byte, obj interface{}) error {
+	if decodesJSONUnions(obj) {
+		return decodeJSONUnions(data, 0, len(data), "", obj, JSON.Codec().Unmarshal)
+	}
+	return JSON.Codec().Unmarshal(data, obj)
+}
+
+// recordErrorOffset 解码错误在记录中的偏移量，无法确定时为 0（记录开头）
+func recordErrorOffset(err error) int64 {
+	var decodeErr *JSONDecodeError
+	if errors.As(err, &decodeErr) {
+		return decodeErr.Offset
+	}
+	return syntaxErrorOffset(err, 0)
+}
+
+// handleBadRecord 根据配置决定跳过坏记录还是返回错误
+func handleBadRecord(streamErr *JSONStreamError, opts JSONStreamOptions) error {
+	if !opts.SkipBadRecords {
+		return streamErr
+	}
+	if opts.OnBadRecord != nil {
+		opts.OnBadRecord(streamErr)
+	}
+	return nil
+}
+
+func expectArrayStart(decoder *json.Decoder, tracker *lineTracker) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return tracker.streamError(-1, syntaxErrorOffset(err, decoder.InputOffset()), err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return tracker.streamError(-1, decoder.InputOffset(), fmt.Errorf("expected JSON array, got %v", token))
+	}
+	return nil
+}
+
+// syntaxErrorOffset 优先使用语法错误中携带的偏移量（SyntaxError.Offset 指向出错字符之后）
+func syntaxErrorOffset(err error, fallback int64) int64 {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) && syntaxErr.Offset > 0 {
+		return syntaxErr.Offset - 1
+	}
+	return fallback
+}
+
+// lineTracker 记录读取过的换行符位置，用于把字节偏移量换算为行列号。
+// 查询的偏移量必须单调不减，已越过的换行符会被丢弃，因此内存占用与输入大小无关。
+type lineTracker struct {
+	r        io.Reader
+	read     int64   // 已读取的字节数
+	newlines []int64 // 尚未越过的换行符偏移量
+	line     int     // 已越过的换行符数量
+	lastLF   int64   // 最后一个已越过的换行符偏移量，没有时为 -1
+}
+
+func (t *lineTracker) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	for idx, b := range p[:n] {
+		if b == '\n' {
+			t.newlines = append(t.newlines, t.read+int64(idx))
+		}
+	}
+	t.read += int64(n)
+	return n, err
+}
+
+// position 返回偏移量对应的行号与列号（均从 1 开始）
+func (t *lineTracker) position(offset int64) (line, column int) {
+	consumed := 0
+	for consumed < len(t.newlines) && t.newlines[consumed] < offset {
+		t.lastLF = t.newlines[consumed]
+		consumed++
+	}
+	t.line += consumed
+	t.newlines = t.newlines[consumed:]
+
+	return t.line + 1, int(offset - t.lastLF)
+}
+
+func (t *lineTracker) streamError(index int, offset int64, err error) *JSONStreamError {
+	line, column := t.position(offset)
+	return &JSONStreamError{Index: index, Line: line, Column: column, Offset: offset, Err: err}
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type JSONStreamTest struct {
+	suite.Suite
+}
+
+func TestJSONStreamTest(t *testing.T) {
+	suite.Run(t, new(JSONStreamTest))
+}
+
+type streamUser struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func (j *JSONStreamTest) TestJSONStreamArray_case1() {
+	input := `[
+		{"name":"zhangsan","age":12},
+		{"name":"lisi","age":13}
+	]`
+
+	var users []streamUser
+	err := JSONStreamArray(strings.NewReader(input), func(user streamUser) error {
+		users = append(users, user)
+		return nil
+	}, JSONStreamOptions{})
+
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []streamUser{{"zhangsan", 12}, {"lisi", 13}}, users)
+}
+
+func (j *JSONStreamTest) TestJSONStreamArray_case2() {
+	input := "[\n{\"name\":\"zhangsan\",\"age\":12},\n  {\"name\":\"lisi\",\"age\":\"abc\"},\n{\"name\":\"wangwu\",\"age\":14}\n]"
+
+	// 默认遇到坏记录即返回，错误中带有位置
+	var count int
+	err := JSONStreamArray(strings.NewReader(input), func(user streamUser) error {
+		count++
+		return nil
+	}, JSONStreamOptions{})
+
+	var streamErr *JSONStreamError
+	require.True(j.T(), errors.As(err, &streamErr))
+	require.Equal(j.T(), 1, count)
+	require.Equal(j.T(), 1, streamErr.Index)
+	require.Equal(j.T(), 3, streamErr.Line)
+	require.Equal(j.T(), 3, streamErr.Column)
+
+	var typeErr *json.UnmarshalTypeError
+	require.True(j.T(), errors.As(err, &typeErr))
+
+	// 跳过坏记录
+	var (
+		names   []string
+		skipped []*JSONStreamError
+	)
+	err = JSONStreamArray(strings.NewReader(input), func(user streamUser) error {
+		names = append(names, user.Name)
+		return nil
+	}, JSONStreamOptions{
+		SkipBadRecords: true,
+		OnBadRecord: func(err *JSONStreamError) {
+			skipped = append(skipped, err)
+		},
+	})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []string{"zhangsan", "wangwu"}, names)
+	require.Len(j.T(), skipped, 1)
+}
+
+func (j *JSONStreamTest) TestJSONStreamArray_case3() {
+	// 语法错误即使开启跳过也会返回
+	input := "[1,\n  {\"a\":x}]"
+	err := JSONStreamArray(strings.NewReader(input), func(item interface{}) error {
+		return nil
+	}, JSONStreamOptions{SkipBadRecords: true})
+
+	var streamErr *JSONStreamError
+	require.True(j.T(), errors.As(err, &streamErr))
+	require.Equal(j.T(), 2, streamErr.Line)
+	require.Equal(j.T(), 8, streamErr.Column)
+	require.Equal(j.T(), int64(11), streamErr.Offset)
+
+	// 顶层不是数组
+	err = JSONStreamArray(strings.NewReader(`{"a":1}`), func(item interface{}) error {
+		return nil
+	}, JSONStreamOptions{})
+	require.True(j.T(), errors.As(err, &streamErr))
+
+	// 数组未闭合
+	err = JSONStreamArray(strings.NewReader(`[1,2`), func(item int) error {
+		return nil
+	}, JSONStreamOptions{})
+	require.NotNil(j.T(), err)
+}
+
+func (j *JSONStreamTest) TestJSONStreamArray_case4() {
+	// 回调提前终止
+	var items []int
+	err := JSONStreamArray(strings.NewReader(`[1,2,3,4]`), func(item int) error {
+		items = append(items, item)
+		if item == 2 {
+			return ErrStreamStop
+		}
+		return nil
+	}, JSONStreamOptions{})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []int{1, 2}, items)
+
+	mockErr := errors.New("mock err")
+	err = JSONStreamArray(strings.NewReader(`[1,2,3,4]`), func(item int) error {
+		return mockErr
+	}, JSONStreamOptions{})
+	require.Equal(j.T(), mockErr, err)
+}
+
+func (j *JSONStreamTest) TestGopherunJSON_StreamArray() {
+	var raws []string
+	err := JSON.StreamArray(strings.NewReader(`[1, "a", {"b":null}]`), func(raw json.RawMessage) error {
+		raws = append(raws, string(raw))
+		return nil
+	}, JSONStreamOptions{})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []string{`1`, `"a"`, `{"b":null}`}, raws)
+}
+
+func (j *JSONStreamTest) TestJSONStreamLines_case1() {
+	input := "{\"name\":\"zhangsan\",\"age\":12}\n\n  {\"name\":\"lisi\",\"age\":13}\r\n{\"name\":\"wangwu\",\"age\":14}"
+
+	var users []streamUser
+	err := JSONStreamLines(strings.NewReader(input), func(user streamUser) error {
+		users = append(users, user)
+		return nil
+	}, JSONStreamOptions{})
+
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []streamUser{{"zhangsan", 12}, {"lisi", 13}, {"wangwu", 14}}, users)
+}
+
+func (j *JSONStreamTest) TestJSONStreamLines_case2() {
+	input := "{\"name\":\"zhangsan\"}\n  {\"name\":x}\n{\"name\":\"wangwu\"}\n"
+
+	err := JSONStreamLines(strings.NewReader(input), func(user streamUser) error {
+		return nil
+	}, JSONStreamOptions{})
+	var streamErr *JSONStreamError
+	require.True(j.T(), errors.As(err, &streamErr))
+	require.Equal(j.T(), 1, streamErr.Index)
+	require.Equal(j.T(), 2, streamErr.Line)
+	require.Equal(j.T(), 11, streamErr.Column)
+	require.Equal(j.T(), int64(30), streamErr.Offset)
+
+	var names []string
+	err = JSONStreamLines(strings.NewReader(input), func(user streamUser) error {
+		names = append(names, user.Name)
+		return nil
+	}, JSONStreamOptions{SkipBadRecords: true})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []string{"zhangsan", "wangwu"}, names)
+}
+
+func (j *JSONStreamTest) TestGopherunJSON_StreamLines() {
+	var count int
+	err := JSON.StreamLines(strings.NewReader("1\n2\n3\n"), func(raw json.RawMessage) error {
+		count++
+		if count == 2 {
+			return ErrStreamStop
+		}
+		return nil
+	}, JSONStreamOptions{})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), 2, count)
+}
@@ -0,0 +1,460 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONViolationKind 校验失败的类别
+type JSONViolationKind string
+
+// 校验失败的类别
+const (
+	JSONViolationUnknownField JSONViolationKind = "unknown_field" // 目标结构体中不存在的字段
+	JSONViolationRequired     JSONViolationKind = "required"      // 缺少必填字段
+	JSONViolationType         JSONViolationKind = "type"          // JSON 类型与目标类型不匹配
+	JSONViolationTrailingData JSONViolationKind = "trailing_data" // 第一个值之后存在多余数据
+)
+
+// JSONViolation 一处校验失败
+type JSONViolation struct {
+	Path    string            // 出错位置，JSON Pointer 格式（RFC 6901），文档根为 ""
+	Kind    JSONViolationKind // 失败类别
+	Message string            // 可读的描述
+}
+
+func (v JSONViolation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "/"
+	}
+	return path + ": " + v.Message
+}
+
+// JSONValidationError 汇总了一次校验中的全部失败
+type JSONValidationError struct {
+	Violations []JSONViolation
+}
+
+func (e *JSONValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.String())
+	}
+	return fmt.Sprintf("json: %d violation(s): %s", len(e.Violations), strings.Join(messages, "; "))
+}
+
+var (
+	_jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	_textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// DecodeStrict 严格模式解码：拒绝未知字段、拒绝第一个值之后的多余数据、校验 `required:"true"` 标记的必填字段
+// （字段缺失或为 null 均视为缺失），并检查 JSON 类型与目标类型是否匹配。
+// 校验失败时返回 *JSONValidationError，其中列出所有出错的路径，此时 obj 不会被修改；
+// 语法错误与类型错误与 Decode 一样包装为 *JSONDecodeError。
+func (i GopherunJSON) DecodeStrict(data []byte, obj interface{}) error {
+	target := reflect.ValueOf(obj)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(obj)}
+	}
+
+	violations, err := checkStrictJSON(data, target.Type().Elem())
+	if err != nil {
+		return newJSONDecodeError(data, err)
+	}
+	if len(violations) > 0 {
+		return &JSONValidationError{Violations: violations}
+	}
+
+	// 未知字段与多余数据已在检查中报告，直接使用 SetCodec 设置的引擎解码
+	if decodesJSONUnions(obj) {
+		return decodeJSONUnions(data, 0, len(data), "", obj, i.Codec().Unmarshal)
+	}
+	if err = i.Codec().Unmarshal(data, obj); err != nil {
+		return newJSONDecodeError(data, err)
+	}
+	return nil
+}
+
+// DecodeStrictByJSONStr 严格模式解码 JSON 字符串，规则同 DecodeStrict
+func (i GopherunJSON) DecodeStrictByJSONStr(jsonStr string, obj interface{}) error {
+	return i.DecodeStrict([]byte(jsonStr), obj)
+}
+
+// checkStrictJSON 对照目标类型检查 JSON 文档，返回全部违规项
+func checkStrictJSON(data []byte, typ reflect.Type) ([]JSONViolation, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	tree, err := decodeOrderedJSONValue(decoder)
+	if err != nil {
+		// 逐个读取记号时的错误位置与提示不够准确（输入为空时甚至是 io.EOF），改用 json.Unmarshal 的语法错误，与 Decode 一致
+		if syntaxErr := json.Unmarshal(data, new(json.RawMessage)); syntaxErr != nil {
+			err = syntaxErr
+		}
+		return nil, err
+	}
+
+	checker := &strictChecker{}
+	checker.check("", tree, typ)
+
+	if rest := bytes.TrimSpace(data[decoder.InputOffset():]); len(rest) > 0 {
+		checker.add("", JSONViolationTrailingData, fmt.Sprintf("unexpected data after top-level value at offset %d", decoder.InputOffset()))
+	}
+	return checker.violations, nil
+}
+
+type strictChecker struct {
+	violations []JSONViolation
+}
+
+func (c *strictChecker) add(path string, kind JSONViolationKind, message string) {
+	c.violations = append(c.violations, JSONViolation{Path: path, Kind: kind, Message: message})
+}
+
+func (c *strictChecker) check(path string, node interface{}, typ reflect.Type) {
+	if node == nil {
+		return
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	// 自定义解码的类型由其自身负责校验
+	if reflect.PtrTo(typ).Implements(_jsonUnmarshalerType) {
+		return
+	}
+	if reflect.PtrTo(typ).Implements(_textUnmarshalerType) {
+		if _, ok := node.(string); !ok {
+			c.typeMismatch(path, node, typ)
+		}
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Interface:
+		if union := _jsonTypes.union(typ); union != nil {
+			c.checkUnion(path, node, typ, union)
+		}
+	case reflect.Struct:
+		object, ok := node.(*orderedJSONObject)
+		if !ok {
+			c.typeMismatch(path, node, typ)
+			return
+		}
+		c.checkStruct(path, object, typ, "")
+	case reflect.Map:
+		object, ok := node.(*orderedJSONObject)
+		if !ok {
+			c.typeMismatch(path, node, typ)
+			return
+		}
+		for idx, key := range object.keys {
+			c.check(path+"/"+escapeJSONPointerToken(key), object.values[idx], typ.Elem())
+		}
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			// []byte 以 base64 字符串编码
+			if _, ok := node.(string); ok {
+				return
+			}
+		}
+		fallthrough
+	case reflect.Array:
+		array, ok := node.([]interface{})
+		if !ok {
+			c.typeMismatch(path, node, typ)
+			return
+		}
+		for idx, item := range array {
+			c.check(path+"/"+strconv.Itoa(idx), item, typ.Elem())
+		}
+	case reflect.String:
+		if _, ok := node.(string); !ok {
+			c.typeMismatch(path, node, typ)
+		}
+	case reflect.Bool:
+		if _, ok := node.(bool); !ok {
+			c.typeMismatch(path, node, typ)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		number, ok := node.(json.Number)
+		if !ok {
+			c.typeMismatch(path, node, typ)
+			return
+		}
+		if !jsonNumberFits(number, typ) {
+			c.add(path, JSONViolationType, fmt.Sprintf("cannot use JSON number %s as Go value of type %s", number, typ))
+		}
+	}
+}
+
+// jsonNumberFits 与 encoding/json 一致：整数类型不接受小数与指数写法，且数值不能超出目标类型的范围
+func jsonNumberFits(number json.Number, typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err := strconv.ParseInt(string(number), 10, typ.Bits())
+		return err == nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		_, err := strconv.ParseUint(string(number), 10, typ.Bits())
+		return err == nil
+	default:
+		_, err := strconv.ParseFloat(string(number), typ.Bits())
+		return err == nil
+	}
+}
+
+// checkUnion 按区分字段找到 JSONRegisterType 注册的具体类型后检查对象
+func (c *strictChecker) checkUnion(path string, node interface{}, typ reflect.Type, union *jsonUnion) {
+	object, ok := node.(*orderedJSONObject)
+	if !ok {
+		c.typeMismatch(path, node, typ)
+		return
+	}
+	value, ok := object.get(union.field)
+	if !ok {
+		c.add(path, JSONViolationType, fmt.Sprintf("%s: missing field %q", ErrUnknownJSONType, union.field))
+		return
+	}
+	name, ok := value.(string)
+	if !ok {
+		c.add(path, JSONViolationType, fmt.Sprintf("%s: field %q must be a string", ErrUnknownJSONType, union.field))
+		return
+	}
+	concrete, ok := union.types[name]
+	if !ok {
+		c.add(path, JSONViolationType, fmt.Sprintf("%s: %q is not registered for %s", ErrUnknownJSONType, name, typ))
+		return
+	}
+
+	if concrete.Kind() == reflect.Ptr {
+		concrete = concrete.Elem()
+	}
+	if !reflect.PtrTo(concrete).Implements(_jsonUnmarshalerType) {
+		c.checkStruct(path, object, concrete, union.field)
+	}
+}
+
+// checkStruct 检查对象的字段，discriminator 为多态类型的区分字段，结构体中没有对应字段时不视为未知字段
+func (c *strictChecker) checkStruct(path string, object *orderedJSONObject, typ reflect.Type, discriminator string) {
+	fields := jsonStructFields(typ)
+	present := map[int]bool{}
+
+	for idx, key := range object.keys {
+		fieldPath := path + "/" + escapeJSONPointerToken(key)
+		fieldIdx := matchJSONField(fields, key)
+		if fieldIdx < 0 && key == discriminator {
+			continue
+		}
+		if fieldIdx < 0 {
+			c.add(fieldPath, JSONViolationUnknownField, fmt.Sprintf("unknown field %q", key))
+			continue
+		}
+
+		field := fields[fieldIdx]
+		if object.values[idx] != nil {
+			present[fieldIdx] = true
+		}
+		// 带 ,string 选项的字段以字符串承载数值，不做类型检查
+		if !field.quoted {
+			c.check(fieldPath, object.values[idx], field.typ)
+		}
+	}
+
+	for idx, field := range fields {
+		if field.required && !present[idx] {
+			c.add(path+"/"+escapeJSONPointerToken(field.name), JSONViolationRequired, fmt.Sprintf("missing required field %q", field.name))
+		}
+	}
+}
+
+func (c *strictChecker) typeMismatch(path string, node interface{}, typ reflect.Type) {
+	c.add(path, JSONViolationType, fmt.Sprintf("cannot use JSON %s as Go value of type %s", jsonNodeKind(node), typ))
+}
+
+// jsonNodeKind 返回树节点对应的 JSON 类型名称
+func jsonNodeKind(node interface{}) string {
+	switch node.(type) {
+	case nil:
+		return "null"
+	case *orderedJSONObject:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", node)
+	}
+}
+
+// jsonField 结构体中参与 JSON 编解码的字段
+type jsonField struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	tag       reflect.StructTag
+	quoted    bool
+	omitEmpty bool
+	required  bool
+}
+
+// jsonStructFields 按 encoding/json 的规则列出结构体字段：匿名嵌入结构体的字段会被提升，浅层字段优先；
+// 同一层有多个同名字段时，只有其中恰好一个带有 json 标签名时保留该字段，否则全部忽略（也会屏蔽更深层的同名字段）
+func jsonStructFields(typ reflect.Type) []jsonField {
+	type candidate struct {
+		field  jsonField
+		tagged bool
+	}
+	var (
+		fields  []jsonField
+		seen    = map[string]bool{}
+		current = []jsonField{{typ: typ}}
+		visited = map[reflect.Type]bool{}
+	)
+
+	for len(current) > 0 {
+		var (
+			next       []jsonField
+			names      []string
+			candidates = map[string][]candidate{}
+		)
+		for _, parent := range current {
+			// 同一类型在同一层出现多次时其字段互相冲突，与 encoding/json 一致全部忽略
+			if visited[parent.typ] {
+				continue
+			}
+
+			for idx := 0; idx < parent.typ.NumField(); idx++ {
+				sf := parent.typ.Field(idx)
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, options, _ := strings.Cut(tag, ",")
+				index := append(append([]int{}, parent.index...), idx)
+
+				fieldType := sf.Type
+				if fieldType.Kind() == reflect.Ptr {
+					fieldType = fieldType.Elem()
+				}
+				if sf.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+					next = append(next, jsonField{typ: fieldType, index: index})
+					continue
+				}
+				if !sf.IsExported() {
+					continue
+				}
+
+				tagged := name != ""
+				if !tagged {
+					name = sf.Name
+				}
+				if seen[name] {
+					continue
+				}
+				if _, ok := candidates[name]; !ok {
+					names = append(names, name)
+				}
+				candidates[name] = append(candidates[name], candidate{tagged: tagged, field: jsonField{
+					name:      name,
+					index:     index,
+					typ:       sf.Type,
+					tag:       sf.Tag,
+					quoted:    hasJSONTagOption(options, "string"),
+					omitEmpty: hasJSONTagOption(options, "omitempty"),
+					required:  sf.Tag.Get("required") == "true",
+				}})
+			}
+		}
+		for _, parent := range current {
+			visited[parent.typ] = true
+		}
+
+		for _, name := range names {
+			seen[name] = true
+			group := candidates[name]
+			if len(group) == 1 {
+				fields = append(fields, group[0].field)
+				continue
+			}
+			var dominant []candidate
+			for _, c := range group {
+				if c.tagged {
+					dominant = append(dominant, c)
+				}
+			}
+			if len(dominant) == 1 {
+				fields = append(fields, dominant[0].field)
+			}
+		}
+		current = next
+	}
+
+	// 与 encoding/json 一致，按字段在结构体中的声明顺序排列
+	sort.Slice(fields, func(a, b int) bool {
+		indexA, indexB := fields[a].index, fields[b].index
+		for idx := 0; idx < len(indexA) && idx < len(indexB); idx++ {
+			if indexA[idx] != indexB[idx] {
+				return indexA[idx] < indexB[idx]
+			}
+		}
+		return len(indexA) < len(indexB)
+	})
+	return fields
+}
+
+func hasJSONTagOption(options, option string) bool {
+	for options != "" {
+		var current string
+		current, options, _ = strings.Cut(options, ",")
+		if current == option {
+			return true
+		}
+	}
+	return false
+}
+
+// matchJSONField 查找键对应的字段，与 encoding/json 一致：优先精确匹配，其次不区分大小写匹配
+func matchJSONField(fields []jsonField, key string) int {
+	for idx, field := range fields {
+		if field.name == key {
+			return idx
+		}
+	}
+	for idx, field := range fields {
+		if strings.EqualFold(field.name, key) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// escapeJSONPointerToken 按 RFC 6901 转义 JSON Pointer 中的单个片段
+func escapeJSONPointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
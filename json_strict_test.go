@@ -0,0 +1,252 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type JSONStrictTest struct {
+	suite.Suite
+}
+
+func TestJSONStrictTest(t *testing.T) {
+	suite.Run(t, new(JSONStrictTest))
+}
+
+type strictBase struct {
+	ID int64 `json:"id" required:"true"`
+}
+
+type strictAddress struct {
+	City string `json:"city" required:"true"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type strictUser struct {
+	strictBase
+	Name      string            `json:"name" required:"true"`
+	Age       int               `json:"age"`
+	Count     int               `json:"count,string"`
+	Tags      []string          `json:"tags"`
+	Address   *strictAddress    `json:"address"`
+	Labels    map[string]int    `json:"labels"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Extra     json.RawMessage   `json:"extra"`
+	Any       interface{}       `json:"any"`
+	Avatar    []byte            `json:"avatar"`
+	Ignored   string            `json:"-"`
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+func (j *JSONStrictTest) TestGopherunJSON_DecodeStrict_case1() {
+	jsonStr := `{
+		"id": 1,
+		"name": "zhangsan",
+		"AGE": 12,
+		"count": "3",
+		"tags": ["a"],
+		"address": {"city": "beijing"},
+		"labels": {"x": 1},
+		"createdAt": "2025-01-02T03:04:05Z",
+		"extra": {"anything": [1, 2]},
+		"any": [true],
+		"avatar": "aGVsbG8="
+	}`
+
+	user := &strictUser{}
+	err := JSON.DecodeStrictByJSONStr(jsonStr, user)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), int64(1), user.ID)
+	require.Equal(j.T(), 12, user.Age)
+	require.Equal(j.T(), 3, user.Count)
+	require.Equal(j.T(), "beijing", user.Address.City)
+	require.Equal(j.T(), "hello", string(user.Avatar))
+}
+
+func (j *JSONStrictTest) TestGopherunJSON_DecodeStrict_case2() {
+	jsonStr := `{
+		"name": null,
+		"age": "12",
+		"unknown": 1,
+		"tags": [1, "b"],
+		"address": {"zip": "100000", "street": "x"},
+		"labels": {"x": "y"},
+		"Ignored": "x",
+		"a/b": 1
+	}`
+
+	user := &strictUser{}
+	err := JSON.DecodeStrictByJSONStr(jsonStr, user)
+
+	var validationErr *JSONValidationError
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), []JSONViolation{
+		{Path: "/age", Kind: JSONViolationType, Message: "cannot use JSON string as Go value of type int"},
+		{Path: "/unknown", Kind: JSONViolationUnknownField, Message: `unknown field "unknown"`},
+		{Path: "/tags/0", Kind: JSONViolationType, Message: "cannot use JSON number as Go value of type string"},
+		{Path: "/address/street", Kind: JSONViolationUnknownField, Message: `unknown field "street"`},
+		{Path: "/address/city", Kind: JSONViolationRequired, Message: `missing required field "city"`},
+		{Path: "/labels/x", Kind: JSONViolationType, Message: "cannot use JSON string as Go value of type int"},
+		{Path: "/Ignored", Kind: JSONViolationUnknownField, Message: `unknown field "Ignored"`},
+		{Path: "/a~1b", Kind: JSONViolationUnknownField, Message: `unknown field "a/b"`},
+		{Path: "/id", Kind: JSONViolationRequired, Message: `missing required field "id"`},
+		{Path: "/name", Kind: JSONViolationRequired, Message: `missing required field "name"`},
+	}, validationErr.Violations)
+
+	// 校验失败时不修改目标
+	require.Equal(j.T(), &strictUser{}, user)
+}
+
+func (j *JSONStrictTest) TestGopherunJSON_DecodeStrict_case3() {
+	var user strictUser
+
+	// 多余数据
+	err := JSON.DecodeStrictByJSONStr(`{"id":1,"name":"a"} {"id":2}`, &user)
+	var validationErr *JSONValidationError
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), JSONViolationTrailingData, validationErr.Violations[0].Kind)
+	require.Contains(j.T(), err.Error(), "1 violation(s): /: unexpected data")
+
+	// 尾随空白是允许的
+	require.Nil(j.T(), JSON.DecodeStrictByJSONStr("{\"id\":1,\"name\":\"a\"}\n\t ", &user))
+
+	// 语法错误原样返回
+	err = JSON.DecodeStrictByJSONStr(`{"id":1,`, &user)
+	require.NotNil(j.T(), err)
+	require.False(j.T(), errors.As(err, &validationErr))
+
+	// 目标不是指针
+	err = JSON.DecodeStrictByJSONStr(`{}`, user)
+	var invalidErr *json.InvalidUnmarshalError
+	require.True(j.T(), errors.As(err, &invalidErr))
+}
+
+func (j *JSONStrictTest) TestGopherunJSON_DecodeStrict_case4() {
+	// 顶层类型不匹配
+	var users []strictUser
+	err := JSON.DecodeStrictByJSONStr(`{"id":1}`, &users)
+	var validationErr *JSONValidationError
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), "", validationErr.Violations[0].Path)
+
+	err = JSON.DecodeStrictByJSONStr(`[{"id":1,"name":"a"},{"id":2}]`, &users)
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), "/1/name", validationErr.Violations[0].Path)
+}
+
+type strictNamed struct {
+	Name string
+}
+
+type strictAlias struct {
+	Name string
+}
+
+type strictTagged struct {
+	Title string `json:"Title"`
+}
+
+type strictUntagged struct {
+	Title string
+}
+
+type strictAmbiguous struct {
+	strictNamed
+	strictAlias
+	Age int `json:"age"`
+}
+
+type strictDominant struct {
+	strictTagged
+	strictUntagged
+}
+
+func (j *JSONStrictTest) TestGopherunJSON_DecodeStrict_case5() {
+	// 同一层的同名字段互相冲突时被忽略，与 encoding/json 一致视为未知字段
+	var ambiguous strictAmbiguous
+	err := JSON.DecodeStrictByJSONStr(`{"Name":"a","age":1}`, &ambiguous)
+	var validationErr *JSONValidationError
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), []JSONViolation{
+		{Path: "/Name", Kind: JSONViolationUnknownField, Message: `unknown field "Name"`},
+	}, validationErr.Violations)
+
+	// 只有一个带 json 标签时该字段优先
+	var dominant strictDominant
+	require.Nil(j.T(), JSON.DecodeStrictByJSONStr(`{"Title":"a"}`, &dominant))
+	require.Equal(j.T(), "a", dominant.strictTagged.Title)
+	require.Equal(j.T(), "", dominant.strictUntagged.Title)
+}
+
+func (j *JSONStrictTest) TestGopherunJSON_DecodeStrict_case6() {
+	type numbers struct {
+		Small int8    `json:"small"`
+		Count uint    `json:"count"`
+		Ratio float32 `json:"ratio"`
+		Total int     `json:"total"`
+	}
+
+	// 小数、负数与超出范围的数值在预检查中报告，obj 不会被部分修改
+	target := numbers{Total: 7}
+	err := JSON.DecodeStrictByJSONStr(`{"total":1,"small":300,"count":-1,"ratio":1e40}`, &target)
+	var validationErr *JSONValidationError
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), []JSONViolation{
+		{Path: "/small", Kind: JSONViolationType, Message: "cannot use JSON number 300 as Go value of type int8"},
+		{Path: "/count", Kind: JSONViolationType, Message: "cannot use JSON number -1 as Go value of type uint"},
+		{Path: "/ratio", Kind: JSONViolationType, Message: "cannot use JSON number 1e40 as Go value of type float32"},
+	}, validationErr.Violations)
+	require.Equal(j.T(), numbers{Total: 7}, target)
+
+	err = JSON.DecodeStrictByJSONStr(`{"total":1.5}`, &target)
+	require.True(j.T(), errors.As(err, &validationErr))
+	require.Equal(j.T(), "/total", validationErr.Violations[0].Path)
+	require.Equal(j.T(), numbers{Total: 7}, target)
+
+	require.Nil(j.T(), JSON.DecodeStrictByJSONStr(`{"total":-3,"small":-128,"count":5,"ratio":0.5}`, &target))
+	require.Equal(j.T(), numbers{Total: -3, Small: -128, Count: 5, Ratio: 0.5}, target)
+}
+
+func (j *JSONStrictTest) TestGopherunJSON_DecodeStrict_decodeError() {
+	// 语法错误与 Decode 一样包装为 *JSONDecodeError，位置相同
+	for _, data := range []string{``, ` `, `{"a":`, `{"a":1,}`, `{"a" 1}`, "{\n\"a\": [1, x]}"} {
+		var obj map[string]interface{}
+		err := JSON.DecodeStrictByJSONStr(data, &obj)
+		var decodeErr *JSONDecodeError
+		require.True(j.T(), errors.As(err, &decodeErr), "%q: %v", data, err)
+		require.Equal(j.T(), JSON.DecodeByJSONStr(data, &obj).Error(), err.Error())
+	}
+
+	// 预检查之外的类型错误（如自定义解码返回的 UnmarshalTypeError）同样包装
+	var target struct {
+		Value strictTypeErrorValue `json:"value"`
+	}
+	err := JSON.DecodeStrictByJSONStr(`{"value": 1}`, &target)
+	var decodeErr *JSONDecodeError
+	require.True(j.T(), errors.As(err, &decodeErr), "%v", err)
+	require.Equal(j.T(), JSON.DecodeByJSONStr(`{"value": 1}`, &target).Error(), err.Error())
+}
+
+// strictTypeErrorValue 解码时总是返回 *json.UnmarshalTypeError
+type strictTypeErrorValue struct{}
+
+func (*strictTypeErrorValue) UnmarshalJSON([]byte) error {
+	return &json.UnmarshalTypeError{Value: "number", Type: reflect.TypeOf(""), Offset: 11}
+}
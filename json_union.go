@@ -0,0 +1,609 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+var (
+	// ErrInvalidTypeRegistration 多态类型注册参数不合法或与已有注册冲突
+	ErrInvalidTypeRegistration = errors.New("invalid JSON type registration")
+
+	// ErrUnknownJSONType 解码多态类型时区分字段缺失或取值未注册
+	ErrUnknownJSONType = errors.New("unknown JSON type discriminator")
+)
+
+// JSONRegisterType 注册多态（可辨识联合）类型：T 为接口类型，field 为 JSON 对象中的区分字段（如 "type"），
+// name 为区分值，sample 为实现 T 的具体类型的值（如 &ClickEvent{} 或 ClickEvent{}）。
+// 注册后 Decode 到 T（包括结构体字段、切片与 map 中的 T）时按区分值选择具体类型，
+// Encode 已注册的具体类型时自动写入区分字段。同一个接口的所有具体类型必须使用相同的 field。
+// 通常在 init 中调用。
+func JSONRegisterType[T any](field, name string, sample T) error {
+	return _jsonTypes.register(reflect.TypeOf((*T)(nil)).Elem(), field, name, reflect.TypeOf(sample))
+}
+
+// JSONMustRegisterType 与 JSONRegisterType 相同，注册失败时 panic
+func JSONMustRegisterType[T any](field, name string, sample T) {
+	if err := JSONRegisterType(field, name, sample); err != nil {
+		panic(err)
+	}
+}
+
+// _jsonTypes 全局多态类型注册表
+var _jsonTypes = newJSONTypeRegistry()
+
+// jsonUnion 一个接口类型的全部具体类型
+type jsonUnion struct {
+	field string
+	types map[string]reflect.Type
+}
+
+// jsonDiscriminator 具体类型编码时写入的区分字段
+type jsonDiscriminator struct {
+	field, name string
+}
+
+type jsonTypeRegistry struct {
+	mu     sync.RWMutex
+	unions map[reflect.Type]*jsonUnion        // 接口类型 -> 具体类型
+	names  map[reflect.Type]jsonDiscriminator // 具体类型（去掉指针）-> 区分字段
+	decode map[reflect.Type]bool              // 类型中是否可能出现已注册的接口，注册时清空
+	encode map[reflect.Type]bool              // 类型的值中是否可能出现已注册的具体类型，注册时清空
+}
+
+func newJSONTypeRegistry() *jsonTypeRegistry {
+	return &jsonTypeRegistry{
+		unions: map[reflect.Type]*jsonUnion{},
+		names:  map[reflect.Type]jsonDiscriminator{},
+	}
+}
+
+func (r *jsonTypeRegistry) register(iface reflect.Type, field, name string, concrete reflect.Type) error {
+	switch {
+	case iface.Kind() != reflect.Interface || iface.NumMethod() == 0:
+		return fmt.Errorf("%w: %s is not a non-empty interface type", ErrInvalidTypeRegistration, iface)
+	case field == "" || name == "":
+		return fmt.Errorf("%w: field and name must not be empty", ErrInvalidTypeRegistration)
+	case concrete == nil:
+		return fmt.Errorf("%w: sample of %s must not be nil", ErrInvalidTypeRegistration, iface)
+	}
+	base := concrete
+	if base.Kind() == reflect.Ptr {
+		base = base.Elem()
+	}
+	if base.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %s must be a struct or a pointer to struct", ErrInvalidTypeRegistration, concrete)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	union, ok := r.unions[iface]
+	if !ok {
+		union = &jsonUnion{field: field, types: map[string]reflect.Type{}}
+	}
+	if union.field != field {
+		return fmt.Errorf("%w: %s already uses discriminator field %q", ErrInvalidTypeRegistration, iface, union.field)
+	}
+	if existing, ok := union.types[name]; ok && existing != concrete {
+		return fmt.Errorf("%w: %q of %s is already registered to %s", ErrInvalidTypeRegistration, name, iface, existing)
+	}
+	if existing, ok := r.names[base]; ok && existing != (jsonDiscriminator{field: field, name: name}) {
+		return fmt.Errorf("%w: %s is already registered as %q=%q", ErrInvalidTypeRegistration, base, existing.field, existing.name)
+	}
+
+	union.types[name] = concrete
+	r.unions[iface] = union
+	r.names[base] = jsonDiscriminator{field: field, name: name}
+	r.decode, r.encode = nil, nil
+	return nil
+}
+
+// empty 是否没有任何注册，此时编解码不做额外处理
+func (r *jsonTypeRegistry) empty() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.unions) == 0
+}
+
+func (r *jsonTypeRegistry) union(typ reflect.Type) *jsonUnion {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.unions[typ]
+}
+
+func (r *jsonTypeRegistry) discriminator(typ reflect.Type) (jsonDiscriminator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.names[typ]
+	return d, ok
+}
+
+// decodes 解码到 typ 时是否需要处理多态类型
+func (r *jsonTypeRegistry) decodes(typ reflect.Type) bool {
+	r.mu.RLock()
+	result, ok := r.decode[typ]
+	r.mu.RUnlock()
+	if ok {
+		return result
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.decode == nil {
+		r.decode = map[reflect.Type]bool{}
+	}
+	return r.contains(typ, r.decode, func(typ reflect.Type) bool {
+		_, ok := r.unions[typ]
+		return ok
+	})
+}
+
+// encodes 编码 typ 类型的值时是否需要写入区分字段
+func (r *jsonTypeRegistry) encodes(typ reflect.Type) bool {
+	r.mu.RLock()
+	result, ok := r.encode[typ]
+	r.mu.RUnlock()
+	if ok {
+		return result
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.encode == nil {
+		r.encode = map[reflect.Type]bool{}
+	}
+	return r.contains(typ, r.encode, func(typ reflect.Type) bool {
+		_, ok := r.names[typ]
+		// 接口的值可能是任意类型
+		return ok || typ.Kind() == reflect.Interface
+	})
+}
+
+// contains 类型中是否包含 match 的类型，结果记录在 cache 中，调用方需持有锁
+func (r *jsonTypeRegistry) contains(typ reflect.Type, cache map[reflect.Type]bool, match func(reflect.Type) bool) bool {
+	if typ == nil {
+		return false
+	}
+	if result, ok := cache[typ]; ok {
+		return result
+	}
+	// 先记为 false，避免递归类型死循环
+	cache[typ] = false
+
+	result := match(typ)
+	if !result {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			result = r.contains(typ.Elem(), cache, match)
+		case reflect.Map:
+			result = r.contains(typ.Key(), cache, match) || r.contains(typ.Elem(), cache, match)
+		case reflect.Struct:
+			for _, field := range jsonStructFields(typ) {
+				if r.contains(field.typ, cache, match) {
+					result = true
+					break
+				}
+			}
+		}
+	}
+	cache[typ] = result
+	return result
+}
+
+// jsonUnmarshalFunc 解码多态值以外部分的函数，如 JSONCodec 的 Unmarshal
+type jsonUnmarshalFunc func(data []byte, obj interface{}) error
+
+// decodesJSONUnions 解码到 obj 时是否需要处理多态类型
+func decodesJSONUnions(obj interface{}) bool {
+	return obj != nil && !_jsonTypes.empty() && _jsonTypes.decodes(reflect.TypeOf(obj))
+}
+
+// unionStep 从外层值到多态值所在位置的一步
+type unionStep struct {
+	field []int         // 结构体字段
+	key   reflect.Value // map 键
+	index int           // 切片或数组下标
+}
+
+// unionLocation 文档中一个待解码的多态值
+type unionLocation struct {
+	steps      []unionStep
+	pointer    string
+	iface      reflect.Type
+	start, end int
+}
+
+// decodeJSONUnions 解码包含多态类型的值：先找出所有多态值的位置并替换为等长的 null，
+// 使用 unmarshal 解码其余部分（错误位置与原文一致），再逐个按区分字段解码多态值并填入。
+// data[start:end] 为当前值，prefix 为其 JSON Pointer。
+func decodeJSONUnions(data []byte, start, end int, prefix string, obj interface{}, unmarshal jsonUnmarshalFunc) error {
+	sub := data[start:end]
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return unmarshal(sub, obj)
+	}
+
+	walker := &unionWalker{data: sub, decoder: json.NewDecoder(bytes.NewReader(sub))}
+	if err := walker.walk(v.Type().Elem(), nil, prefix); err != nil {
+		// 文档本身有误时以 unmarshal 的错误为准
+		if codecErr := unmarshal(sub, obj); codecErr != nil {
+			err = codecErr
+		}
+		return relocateJSONDecodeError(data, start, prefix, newJSONDecodeError(sub, err))
+	}
+
+	blanked := append([]byte{}, sub...)
+	for _, location := range walker.locations {
+		if location.end-location.start < len("null") {
+			// 不足以替换为 null 的值（如 {}、1）必然无法解码为多态类型，直接报错
+			_, err := decodeJSONUnionValue(data, start+location.start, start+location.end, location, unmarshal)
+			return err
+		}
+		copy(blanked[location.start:location.end], "null")
+		for idx := location.start + 4; idx < location.end; idx++ {
+			blanked[idx] = ' '
+		}
+	}
+	if err := unmarshal(blanked, obj); err != nil {
+		return relocateJSONDecodeError(data, start, prefix, newJSONDecodeError(sub, err))
+	}
+
+	for _, location := range walker.locations {
+		value, err := decodeJSONUnionValue(data, start+location.start, start+location.end, location, unmarshal)
+		if err != nil {
+			return err
+		}
+		setJSONUnionValue(v.Elem(), location.steps, value)
+	}
+	return nil
+}
+
+// decodeJSONUnionValue 按区分字段选择具体类型并解码 data[start:end]
+func decodeJSONUnionValue(data []byte, start, end int, location unionLocation, unmarshal jsonUnmarshalFunc) (reflect.Value, error) {
+	union := _jsonTypes.union(location.iface)
+	fail := func(format string, args ...interface{}) (reflect.Value, error) {
+		err := fmt.Errorf("%w: "+format, append([]interface{}{ErrUnknownJSONType}, args...)...)
+		decodeErr := &JSONDecodeError{Offset: int64(start), Path: location.pointer, Expected: location.iface.String(), Err: err}
+		decodeErr.Line, decodeErr.Column, decodeErr.Snippet = jsonErrorSnippet(data, start)
+		decodeErr.Actual = jsonLiteralKind(data, start)
+		return reflect.Value{}, decodeErr
+	}
+
+	var head map[string]json.RawMessage
+	if err := json.Unmarshal(data[start:end], &head); err != nil {
+		return fail("%s must be decoded from a JSON object", location.iface)
+	}
+	raw, ok := head[union.field]
+	if !ok {
+		return fail("missing field %q", union.field)
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return fail("field %q must be a string", union.field)
+	}
+	concrete, ok := union.types[name]
+	if !ok {
+		return fail("%q is not registered for %s", name, location.iface)
+	}
+
+	elemType := concrete
+	if concrete.Kind() == reflect.Ptr {
+		elemType = concrete.Elem()
+	}
+	target := reflect.New(elemType)
+	if err := decodeJSONUnions(data, start, end, location.pointer, target.Interface(), unmarshal); err != nil {
+		return reflect.Value{}, err
+	}
+	if concrete.Kind() == reflect.Ptr {
+		return target, nil
+	}
+	return target.Elem(), nil
+}
+
+// setJSONUnionValue 沿 steps 找到多态值的位置并赋值，途经的 nil 指针会被分配
+func setJSONUnionValue(v reflect.Value, steps []unionStep, value reflect.Value) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if len(steps) == 0 {
+		v.Set(value)
+		return
+	}
+
+	step := steps[0]
+	switch {
+	case step.field != nil:
+		field, err := v.FieldByIndexErr(step.field)
+		if err != nil {
+			return
+		}
+		setJSONUnionValue(field, steps[1:], value)
+	case step.key.IsValid():
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		// map 的元素不可寻址，修改副本后写回
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(step.key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		setJSONUnionValue(elem, steps[1:], value)
+		v.SetMapIndex(step.key, elem)
+	default:
+		if step.index < v.Len() {
+			setJSONUnionValue(v.Index(step.index), steps[1:], value)
+		}
+	}
+}
+
+// relocateJSONDecodeError 将子文档 data[start:] 中的错误位置换算到整个文档
+func relocateJSONDecodeError(data []byte, start int, prefix string, err error) error {
+	var decodeErr *JSONDecodeError
+	if start == 0 || !errors.As(err, &decodeErr) {
+		return err
+	}
+	decodeErr.Offset += int64(start)
+	decodeErr.Path = prefix + decodeErr.Path
+	decodeErr.Line, decodeErr.Column, decodeErr.Snippet = jsonErrorSnippet(data, int(decodeErr.Offset))
+	return decodeErr
+}
+
+// unionWalker 对照 Go 类型逐个读取 JSON 记号，记录所有多态值的位置
+type unionWalker struct {
+	data      []byte
+	decoder   *json.Decoder
+	locations []unionLocation
+}
+
+func (w *unionWalker) walk(typ reflect.Type, steps []unionStep, pointer string) error {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if !_jsonTypes.decodes(typ) || reflect.PtrTo(typ).Implements(_jsonUnmarshalerType) {
+		return w.skip()
+	}
+	if _jsonTypes.union(typ) != nil {
+		return w.record(typ, steps, pointer)
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		if w.peek() != '{' {
+			return w.skip()
+		}
+		fields := jsonStructFields(typ)
+		return w.object(func(key string) error {
+			idx := matchJSONField(fields, key)
+			if idx < 0 {
+				return w.skip()
+			}
+			return w.walk(fields[idx].typ, append(steps, unionStep{field: fields[idx].index}), pointer+"/"+escapeJSONPointerToken(key))
+		})
+	case reflect.Map:
+		if w.peek() != '{' {
+			return w.skip()
+		}
+		return w.object(func(key string) error {
+			mapKey, ok := jsonMapKey(typ.Key(), key)
+			if !ok {
+				return w.skip()
+			}
+			return w.walk(typ.Elem(), append(steps, unionStep{key: mapKey}), pointer+"/"+escapeJSONPointerToken(key))
+		})
+	case reflect.Slice, reflect.Array:
+		if w.peek() != '[' {
+			return w.skip()
+		}
+		if _, err := w.decoder.Token(); err != nil {
+			return err
+		}
+		for idx := 0; w.decoder.More(); idx++ {
+			var err error
+			if typ.Kind() == reflect.Array && idx >= typ.Len() {
+				err = w.skip()
+			} else {
+				err = w.walk(typ.Elem(), append(steps, unionStep{index: idx}), pointer+"/"+strconv.Itoa(idx))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		_, err := w.decoder.Token()
+		return err
+	default:
+		return w.skip()
+	}
+}
+
+// object 读取一个 JSON 对象，每个键的值交给 fn 处理
+func (w *unionWalker) object(fn func(key string) error) error {
+	if _, err := w.decoder.Token(); err != nil {
+		return err
+	}
+	for w.decoder.More() {
+		token, err := w.decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := token.(string)
+		if err = fn(key); err != nil {
+			return err
+		}
+	}
+	_, err := w.decoder.Token()
+	return err
+}
+
+// record 记录多态值的位置，null 保持为 nil
+func (w *unionWalker) record(iface reflect.Type, steps []unionStep, pointer string) error {
+	from := int(w.decoder.InputOffset())
+	var raw json.RawMessage
+	if err := w.decoder.Decode(&raw); err != nil {
+		return err
+	}
+	if bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+	to := int(w.decoder.InputOffset())
+	start := from + bytes.Index(w.data[from:to], raw)
+	w.locations = append(w.locations, unionLocation{
+		steps:   append([]unionStep{}, steps...),
+		pointer: pointer,
+		iface:   iface,
+		start:   start,
+		end:     start + len(raw),
+	})
+	return nil
+}
+
+func (w *unionWalker) skip() error {
+	var raw json.RawMessage
+	return w.decoder.Decode(&raw)
+}
+
+// peek 返回下一个值的首字符
+func (w *unionWalker) peek() byte {
+	for _, ch := range w.data[w.decoder.InputOffset():] {
+		switch ch {
+		case ' ', '\t', '\n', '\r', ':', ',':
+		default:
+			return ch
+		}
+	}
+	return 0
+}
+
+// hasJSONDiscriminators 值中是否出现了已注册的具体类型，编码时据此决定是否需要写入区分字段；
+// 只沿可能包含已注册类型的字段与元素查找，例如 []int 不会逐个检查元素
+func hasJSONDiscriminators(v reflect.Value) bool {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || !_jsonTypes.encodes(v.Type()) {
+		return false
+	}
+	if _, ok := _jsonTypes.discriminator(v.Type()); ok {
+		return true
+	}
+	if marshalsJSONItself(v.Type()) {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for idx := 0; idx < v.Len(); idx++ {
+			if hasJSONDiscriminators(v.Index(idx)) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if hasJSONDiscriminators(iter.Value()) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		for _, field := range jsonStructFields(v.Type()) {
+			if !_jsonTypes.encodes(field.typ) {
+				continue
+			}
+			if fieldValue, err := v.FieldByIndexErr(field.index); err == nil && hasJSONDiscriminators(fieldValue) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// marshalsJSONItself 类型是否自行编码（实现了 json.Marshaler 或 encoding.TextMarshaler）
+func marshalsJSONItself(typ reflect.Type) bool {
+	return typ.Implements(_jsonMarshalerType) || reflect.PtrTo(typ).Implements(_jsonMarshalerType) ||
+		typ.Implements(_textMarshalerType) || reflect.PtrTo(typ).Implements(_textMarshalerType)
+}
+
+// addJSONDiscriminators 对照 Go 值，为已注册的具体类型编码得到的对象写入区分字段（已存在时不覆盖）
+func addJSONDiscriminators(node interface{}, v reflect.Value, fieldCache map[reflect.Type]map[string][]int) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return
+	}
+
+	object, isObject := node.(*orderedJSONObject)
+	if d, ok := _jsonTypes.discriminator(v.Type()); ok && isObject {
+		if _, exists := object.get(d.field); !exists {
+			object.keys = append([]string{d.field}, object.keys...)
+			object.values = append([]interface{}{d.name}, object.values...)
+		}
+	}
+	if marshalsJSONItself(v.Type()) {
+		return
+	}
+
+	switch value := node.(type) {
+	case []interface{}:
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			for idx := range value {
+				if idx < v.Len() {
+					addJSONDiscriminators(value[idx], v.Index(idx), fieldCache)
+				}
+			}
+		}
+	case *orderedJSONObject:
+		switch v.Kind() {
+		case reflect.Struct:
+			fields, ok := fieldCache[v.Type()]
+			if !ok {
+				fields = map[string][]int{}
+				for _, field := range jsonStructFields(v.Type()) {
+					fields[field.name] = field.index
+				}
+				fieldCache[v.Type()] = fields
+			}
+			for idx, key := range value.keys {
+				if index, ok := fields[key]; ok {
+					if fieldValue, err := v.FieldByIndexErr(index); err == nil {
+						addJSONDiscriminators(value.values[idx], fieldValue, fieldCache)
+					}
+				}
+			}
+		case reflect.Map:
+			for idx, key := range value.keys {
+				if mapKey, ok := jsonMapKey(v.Type().Key(), key); ok {
+					if element := v.MapIndex(mapKey); element.IsValid() {
+						addJSONDiscriminators(value.values[idx], element, fieldCache)
+					}
+				}
+			}
+		}
+	}
+}
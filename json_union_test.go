@@ -0,0 +1,333 @@
+/*
+ * Copyright (c) 2025 TootsCharlie
+ * Gopherun is licensed under Mulan PSL v2.
+ * You can use this software according to the terms and conditions of the Mulan PSL v2.
+ * You may obtain a copy of Mulan PSL v2 at:
+ *          http://license.coscl.org.cn/MulanPSL2
+ * THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+ * EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+ * MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+ * See the Mulan PSL v2 for more details.
+ */
+
+package gopherun
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type JSONUnionTest struct {
+	suite.Suite
+	registry *jsonTypeRegistry
+}
+
+func TestJSONUnionTest(t *testing.T) {
+	suite.Run(t, new(JSONUnionTest))
+}
+
+type unionEvent interface {
+	EventName() string
+}
+
+type unionClick struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func (*unionClick) EventName() string { return "click" }
+
+type unionGroup struct {
+	Title    string       `json:"title"`
+	Children []unionEvent `json:"children"`
+}
+
+func (unionGroup) EventName() string { return "group" }
+
+type unionEnvelope struct {
+	ID      int                   `json:"id"`
+	Event   unionEvent            `json:"event"`
+	Events  []unionEvent          `json:"events"`
+	ByName  map[string]unionEvent `json:"byName"`
+	Pointer *unionEvent           `json:"pointer"`
+	Extra   interface{}           `json:"extra"`
+}
+
+// SetupSuite 在独立的注册表中注册，结束后恢复全局注册表，不影响其他测试
+func (j *JSONUnionTest) SetupSuite() {
+	j.registry = _jsonTypes
+	_jsonTypes = newJSONTypeRegistry()
+	JSONMustRegisterType[unionEvent]("type", "click", &unionClick{})
+	JSONMustRegisterType[unionEvent]("type", "group", unionGroup{})
+}
+
+func (j *JSONUnionTest) TearDownSuite() {
+	_jsonTypes = j.registry
+}
+
+func (j *JSONUnionTest) TearDownTest() {
+	JSON.SetCodec(nil)
+}
+
+func (j *JSONUnionTest) TestGopherunJSON_Decode_union() {
+	data := `{
+		"id": 1,
+		"event": {"type": "click", "x": 1, "y": 2},
+		"events": [
+			{"type": "group", "title": "g", "children": [{"type": "click", "x": 3}, null]},
+			{"y": 4, "type": "click"}
+		],
+		"byName": {"a": {"type": "group", "title": "a"}},
+		"pointer": {"type": "click"},
+		"extra": {"type": "click"}
+	}`
+
+	var envelope unionEnvelope
+	require.Nil(j.T(), JSON.DecodeByJSONStr(data, &envelope))
+	var pointer unionEvent = &unionClick{}
+	require.Equal(j.T(), unionEnvelope{
+		ID:    1,
+		Event: &unionClick{X: 1, Y: 2},
+		Events: []unionEvent{
+			unionGroup{Title: "g", Children: []unionEvent{&unionClick{X: 3}, nil}},
+			&unionClick{Y: 4},
+		},
+		ByName:  map[string]unionEvent{"a": unionGroup{Title: "a"}},
+		Pointer: &pointer,
+		Extra:   map[string]interface{}{"type": "click"},
+	}, envelope)
+
+	// 直接解码到接口
+	var event unionEvent
+	require.Nil(j.T(), JSON.DecodeByJSONStr(`{"type":"click","x":5}`, &event))
+	require.Equal(j.T(), &unionClick{X: 5}, event)
+	require.Nil(j.T(), JSON.DecodeByJSONStr(`null`, &event))
+	require.Nil(j.T(), event)
+
+	events, err := JSONDecodeStrAs[[]unionEvent](`[{"type":"group","title":"x"}]`)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []unionEvent{unionGroup{Title: "x"}}, events)
+}
+
+func (j *JSONUnionTest) TestGopherunJSON_Decode_unionError() {
+	var (
+		envelope  unionEnvelope
+		decodeErr *JSONDecodeError
+	)
+	cases := []struct {
+		data   string
+		path   string
+		column int
+	}{
+		{`{"event": {"type": "tap"}}`, "/event", 11},
+		{`{"event": {"x": 1}}`, "/event", 11},
+		{`{"event": {"type": 1}}`, "/event", 11},
+		{`{"event": {}}`, "/event", 11},
+		{`{"event": "click"}`, "/event", 11},
+		{`{"events": [{"type": "group", "children": [{"type": "?"}]}]}`, "/events/0/children/0", 44},
+	}
+	for _, c := range cases {
+		err := JSON.DecodeByJSONStr(c.data, &envelope)
+		require.True(j.T(), errors.Is(err, ErrUnknownJSONType), "%s: %v", c.data, err)
+		require.True(j.T(), errors.As(err, &decodeErr), c.data)
+		require.Equal(j.T(), c.path, decodeErr.Path, c.data)
+		require.Equal(j.T(), c.column, decodeErr.Column, c.data)
+		require.Equal(j.T(), "gopherun.unionEvent", decodeErr.Expected, c.data)
+	}
+
+	// 其他错误的位置与原文一致
+	err := JSON.DecodeByJSONStr(`{"event": {"type": "click", "x": "1"}, "id": "x"}`, &envelope)
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.Equal(j.T(), "/id", decodeErr.Path)
+	require.Equal(j.T(), 46, decodeErr.Column)
+
+	err = JSON.DecodeByJSONStr(`{"events": [{"type": "click", "x": "1"}]}`, &envelope)
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.Equal(j.T(), "/events/0/x", decodeErr.Path)
+	require.Equal(j.T(), 36, decodeErr.Column)
+
+	err = JSON.DecodeByJSONStr(`{"events": [{"type": "click"}`, &envelope)
+	var syntaxErr *json.SyntaxError
+	require.True(j.T(), errors.As(err, &syntaxErr))
+}
+
+func (j *JSONUnionTest) TestGopherunJSON_Encode_union() {
+	var pointer unionEvent = &unionClick{X: 9}
+	envelope := unionEnvelope{
+		ID:      1,
+		Event:   &unionClick{X: 1, Y: 2},
+		Events:  []unionEvent{unionGroup{Title: "<g>", Children: []unionEvent{&unionClick{}}}},
+		ByName:  map[string]unionEvent{"a": unionGroup{}},
+		Pointer: &pointer,
+	}
+	jsonStr, err := JSON.EncodeToJSONStr(envelope)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"id":1,"event":{"type":"click","x":1,"y":2},"events":[{"type":"group","title":"\u003cg\u003e","children":[{"type":"click","x":0,"y":0}]}],"byName":{"a":{"type":"group","title":"","children":null}},"pointer":{"type":"click","x":9,"y":0},"extra":null}`, jsonStr)
+
+	// 编码后可以原样解码
+	var decoded unionEnvelope
+	require.Nil(j.T(), JSON.DecodeByJSONStr(jsonStr, &decoded))
+	require.Equal(j.T(), envelope, decoded)
+
+	jsonStr, err = JSON.EncodeToJSONStr(&unionClick{X: 1})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"type":"click","x":1,"y":0}`, jsonStr)
+
+	data, err := JSON.EncodeWithOptions(map[string]interface{}{"b": unionGroup{}, "a": 1}, JSONEncodeOptions{SortKeys: true})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"a":1,"b":{"children":null,"title":"","type":"group"}}`, string(data))
+
+	// 未注册的类型不受影响
+	jsonStr, err = JSON.EncodeToJSONStr(map[string]interface{}{"x": []int{1}})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"x":[1]}`, jsonStr)
+}
+
+func (j *JSONUnionTest) TestGopherunJSON_DecodeStrict_union() {
+	var envelope unionEnvelope
+	require.Nil(j.T(), JSON.DecodeStrictByJSONStr(`{"event":{"type":"click","x":1},"events":[{"type":"group","children":[{"type":"click"}]}]}`, &envelope))
+	require.Equal(j.T(), unionEnvelope{
+		Event:  &unionClick{X: 1},
+		Events: []unionEvent{unionGroup{Children: []unionEvent{&unionClick{}}}},
+	}, envelope)
+
+	// 按具体类型检查，区分字段不视为未知字段
+	err := JSON.DecodeStrictByJSONStr(`{"event":{"type":"click","x":"1","z":1},"events":[{"type":"tap"},{"x":1},1]}`, &envelope)
+	var validationErr *JSONValidationError
+	require.True(j.T(), errors.As(err, &validationErr))
+	kinds := map[string]JSONViolationKind{}
+	for _, violation := range validationErr.Violations {
+		kinds[violation.Path] = violation.Kind
+	}
+	require.Equal(j.T(), map[string]JSONViolationKind{
+		"/event/x":  JSONViolationType,
+		"/event/z":  JSONViolationUnknownField,
+		"/events/0": JSONViolationType,
+		"/events/1": JSONViolationType,
+		"/events/2": JSONViolationType,
+	}, kinds)
+}
+
+func (j *JSONUnionTest) TestGopherunJSON_DecodeWithOptions_union() {
+	var envelope unionEnvelope
+	require.Nil(j.T(), JSON.DecodeByJSONStrWithOptions(`{"event":{"type":"click","x":1},"extra":{"n":1}}`, &envelope, JSONDecodeOptions{UseNumber: true}))
+	require.Equal(j.T(), unionEnvelope{
+		Event: &unionClick{X: 1},
+		Extra: map[string]interface{}{"n": json.Number("1")},
+	}, envelope)
+
+	// 错误定位到多态值，而不是文档开头
+	var decodeErr *JSONDecodeError
+	err := JSON.DecodeByJSONStrWithOptions(`{"event": {"type": "tap"}}`, &envelope, JSONDecodeOptions{UseNumber: true})
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.True(j.T(), errors.Is(err, ErrUnknownJSONType))
+	require.Equal(j.T(), "/event", decodeErr.Path)
+	require.Equal(j.T(), 11, decodeErr.Column)
+
+	err = JSON.DecodeByJSONStrWithOptions(`{"event": {"type": "click", "x": "1"}}`, &envelope, JSONDecodeOptions{UseNumber: true})
+	require.True(j.T(), errors.As(err, &decodeErr))
+	require.Equal(j.T(), "/event/x", decodeErr.Path)
+	require.Equal(j.T(), 34, decodeErr.Column)
+}
+
+func (j *JSONUnionTest) TestJSONStream_union() {
+	var (
+		events []unionEvent
+		bad    []*JSONStreamError
+	)
+	opts := JSONStreamOptions{SkipBadRecords: true, OnBadRecord: func(err *JSONStreamError) { bad = append(bad, err) }}
+	err := JSONStreamLines(strings.NewReader("{\"type\":\"click\",\"x\":1}\n  {\"type\":\"tap\"}\n"), func(item unionEvent) error {
+		events = append(events, item)
+		return nil
+	}, opts)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []unionEvent{&unionClick{X: 1}}, events)
+	require.Len(j.T(), bad, 1)
+	require.True(j.T(), errors.Is(bad[0], ErrUnknownJSONType))
+	require.Equal(j.T(), 2, bad[0].Line)
+	require.Equal(j.T(), 3, bad[0].Column)
+
+	var envelopes []unionEnvelope
+	bad = nil
+	err = JSONStreamArray(strings.NewReader(`[{"event":{"type":"click"}}, {"event":{"type":"?"}}]`), func(item unionEnvelope) error {
+		envelopes = append(envelopes, item)
+		return nil
+	}, opts)
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), []unionEnvelope{{Event: &unionClick{}}}, envelopes)
+	require.Len(j.T(), bad, 1)
+	require.True(j.T(), errors.Is(bad[0], ErrUnknownJSONType))
+	require.Equal(j.T(), 1, bad[0].Index)
+	require.Equal(j.T(), 39, bad[0].Column)
+}
+
+func (j *JSONUnionTest) TestGopherunJSON_Encode_unionFastPath() {
+	// rawHTMLCodec 不转义 HTML 字符，输出中出现转义说明结果经过了重新编码
+	JSON.SetCodec(rawHTMLCodec{})
+
+	// 值中没有已注册的具体类型时直接返回引擎的结果
+	jsonStr, err := JSON.EncodeToJSONStr(map[string]interface{}{"html": "<b>", "events": []unionEvent{nil}})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"events":[null],"html":"<b>"}`, jsonStr)
+
+	jsonStr, err = JSON.EncodeToJSONStr(map[string]interface{}{"html": "<b>", "event": &unionClick{}})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"event":{"type":"click","x":0,"y":0},"html":"\u003cb\u003e"}`, jsonStr)
+
+	// 空注册表不做任何额外处理
+	registry := _jsonTypes
+	_jsonTypes = newJSONTypeRegistry()
+	defer func() { _jsonTypes = registry }()
+
+	jsonStr, err = JSON.EncodeToJSONStr(map[string]interface{}{"html": "<b>", "event": &unionClick{}})
+	require.Nil(j.T(), err)
+	require.Equal(j.T(), `{"event":{"x":0,"y":0},"html":"<b>"}`, jsonStr)
+
+	var envelope unionEnvelope
+	err = JSON.DecodeByJSONStr(`{"event":{"type":"click"}}`, &envelope)
+	var typeErr *json.UnmarshalTypeError
+	require.True(j.T(), errors.As(err, &typeErr))
+}
+
+type unionShape interface {
+	Area() float64
+}
+
+type unionSquare struct{}
+
+func (unionSquare) Area() float64 { return 0 }
+
+type unionCircle struct{}
+
+func (unionCircle) Area() float64 { return 0 }
+
+type unionNumber float64
+
+func (unionNumber) Area() float64 { return 0 }
+
+func (j *JSONUnionTest) TestJSONRegisterType() {
+	require.Nil(j.T(), JSONRegisterType[unionShape]("kind", "square", unionSquare{}))
+	// 重复注册相同的类型是允许的
+	require.Nil(j.T(), JSONRegisterType[unionShape]("kind", "square", unionSquare{}))
+
+	cases := []error{
+		JSONRegisterType[unionShape]("type", "circle", unionCircle{}),
+		JSONRegisterType[unionShape]("kind", "square", unionCircle{}),
+		JSONRegisterType[unionShape]("kind", "box", unionSquare{}),
+		JSONRegisterType[unionShape]("kind", "number", unionNumber(0)),
+		JSONRegisterType[unionShape]("kind", "nil", nil),
+		JSONRegisterType[unionShape]("", "circle", unionCircle{}),
+		JSONRegisterType[interface{}]("kind", "circle", unionCircle{}),
+		JSONRegisterType[unionCircle]("kind", "circle", unionCircle{}),
+	}
+	for idx, err := range cases {
+		require.True(j.T(), errors.Is(err, ErrInvalidTypeRegistration), "%d: %v", idx, err)
+	}
+	require.Panics(j.T(), func() {
+		JSONMustRegisterType[unionShape]("type", "circle", unionCircle{})
+	})
+}